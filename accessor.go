@@ -0,0 +1,239 @@
+// This file implements sorting of collections accessed by index.
+
+package sorthelper
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// An Accessor gives indexed access to the elements of a collection that isn't
+// a slice, such as a paged or memory-mapped structure: Get returns the element
+// at index i and Set replaces it with e.
+type Accessor[E any] interface {
+	Len() int
+	Get(i int) E
+	Set(i int, e E)
+}
+
+// accessorBuffer is the number of elements buffered in memory
+// by SortAccessor, StableAccessor and IntsAccessor.
+const accessorBuffer = 1 << 12
+
+// SortAccessor sorts the elements of a as determined by less, as used by Sorter,
+// without reading the whole collection into memory: it sorts blocks of up to
+// 4096 elements in a buffer, with the algorithms of Sorter, then merges the
+// sorted blocks through Get and Set, using the buffer when one of the runs
+// merged fits in it and rotations otherwise.
+// It takes O(n log n) comparisons and O(n log n log(n/4096)) calls of Get and Set
+// in the worst case, and memory for 4096 elements, plus the scratch space
+// of the algorithms sorting the blocks.
+//
+// The sort is not guaranteed to be stable. For a stable sort, use StableAccessor.
+func SortAccessor[E any](a Accessor[E], less func(e1, e2 *E) bool) {
+	newAccessorSorter(a, less, accessorBuffer).sort(func(s []E) { NewSorter(s).OrderedBy(less) })
+}
+
+// StableAccessor is like SortAccessor but keeps the original order of equal elements.
+func StableAccessor[E any](a Accessor[E], less func(e1, e2 *E) bool) {
+	StableAccessorBuffer(a, less, accessorBuffer)
+}
+
+// StableAccessorBuffer is like StableAccessor but buffers up to n elements
+// in memory instead of 4096. An n below 1 is treated as 1, which sorts
+// by merging in place through Get and Set only.
+func StableAccessorBuffer[E any](a Accessor[E], less func(e1, e2 *E) bool, n int) {
+	newAccessorSorter(a, less, n).sort(func(s []E) { stableSort(s, less, -1) })
+}
+
+// IntsAccessor sorts the integers of a in increasing order, like SortAccessor,
+// sorting the blocks with Ints.
+func IntsAccessor[E constraints.Integer](a Accessor[E]) {
+	newAccessorSorter(a, orderedLess[E], accessorBuffer).sort(Ints[E])
+}
+
+// accessorSorter sorts an Accessor with a buffer of a bounded number of elements.
+type accessorSorter[E any] struct {
+	a    Accessor[E]
+	less func(e1, e2 *E) bool
+	buf  []E
+}
+
+func newAccessorSorter[E any](a Accessor[E], less func(e1, e2 *E) bool, n int) *accessorSorter[E] {
+	if n > a.Len() {
+		n = a.Len()
+	}
+	if n < 1 {
+		n = 1
+	}
+	return &accessorSorter[E]{a, less, make([]E, n)}
+}
+
+// sort sorts the blocks of len(x.buf) elements with sortBlock,
+// then merges them bottom-up.
+func (x *accessorSorter[E]) sort(sortBlock func(s []E)) {
+	n, b := x.a.Len(), len(x.buf)
+	for lo := 0; lo < n; lo += b {
+		hi := lo + b
+		if hi > n {
+			hi = n
+		}
+		block := x.buf[:hi-lo]
+		for i := range block {
+			block[i] = x.a.Get(lo + i)
+		}
+		sortBlock(block)
+		for i := range block {
+			x.a.Set(lo+i, block[i])
+		}
+	}
+
+	for width := b; width < n; width *= 2 {
+		for lo := 0; lo+width < n; lo += 2 * width {
+			hi := lo + 2*width
+			if hi > n {
+				hi = n
+			}
+			x.merge(lo, lo+width, hi)
+		}
+	}
+}
+
+// merge merges the sorted runs [lo, mid) and [mid, hi), stably.
+func (x *accessorSorter[E]) merge(lo, mid, hi int) {
+	if lo == mid || mid == hi {
+		return
+	}
+	if e1, e2 := x.a.Get(mid), x.a.Get(mid-1); !x.less(&e1, &e2) {
+		return // Already in order.
+	}
+	switch {
+	case mid-lo <= len(x.buf):
+		x.mergeLo(lo, mid, hi)
+	case hi-mid <= len(x.buf):
+		x.mergeHi(lo, mid, hi)
+	default:
+		x.mergeInPlace(lo, mid, hi)
+	}
+}
+
+// mergeLo merges the runs [lo, mid) and [mid, hi), where the first run
+// fits in the buffer, from the front.
+func (x *accessorSorter[E]) mergeLo(lo, mid, hi int) {
+	buf := x.buf[:mid-lo]
+	for i := range buf {
+		buf[i] = x.a.Get(lo + i)
+	}
+
+	i, j, k := 0, mid, lo
+	e := x.a.Get(j)
+	for i < len(buf) && j < hi {
+		if x.less(&e, &buf[i]) {
+			x.a.Set(k, e)
+			if j++; j < hi {
+				e = x.a.Get(j)
+			}
+		} else {
+			x.a.Set(k, buf[i])
+			i++
+		}
+		k++
+	}
+	for ; i < len(buf); i, k = i+1, k+1 {
+		x.a.Set(k, buf[i])
+	}
+}
+
+// mergeHi merges the runs [lo, mid) and [mid, hi), where the second run
+// fits in the buffer, from the back.
+func (x *accessorSorter[E]) mergeHi(lo, mid, hi int) {
+	buf := x.buf[:hi-mid]
+	for i := range buf {
+		buf[i] = x.a.Get(mid + i)
+	}
+
+	i, j, k := mid-1, len(buf)-1, hi-1
+	e := x.a.Get(i)
+	for i >= lo && j >= 0 {
+		if x.less(&buf[j], &e) {
+			x.a.Set(k, e)
+			if i--; i >= lo {
+				e = x.a.Get(i)
+			}
+		} else {
+			x.a.Set(k, buf[j])
+			j--
+		}
+		k--
+	}
+	for ; j >= 0; j, k = j-1, k-1 {
+		x.a.Set(k, buf[j])
+	}
+}
+
+// mergeInPlace merges the runs [lo, mid) and [mid, hi) with the SymMerge
+// algorithm, like stableSorter.mergeInPlace, down to runs that fit in the buffer.
+func (x *accessorSorter[E]) mergeInPlace(lo, mid, hi int) {
+	n, m := hi-lo, mid-lo
+	half := n / 2
+	p := half + m
+	var start, r int
+	if m > half {
+		start, r = p-n, half
+	} else {
+		start, r = 0, m
+	}
+	for start < r {
+		c := int(uint(start+r) >> 1)
+		if e1, e2 := x.a.Get(lo+p-1-c), x.a.Get(lo+c); !x.less(&e1, &e2) {
+			start = c + 1
+		} else {
+			r = c
+		}
+	}
+	end := p - start
+	if start < m && m < end {
+		x.reverse(lo+start, mid)
+		x.reverse(mid, lo+end)
+		x.reverse(lo+start, lo+end)
+	}
+	x.merge(lo, lo+start, lo+half)
+	x.merge(lo+half, lo+end, hi)
+}
+
+// reverse reverses the elements [lo, hi).
+func (x *accessorSorter[E]) reverse(lo, hi int) {
+	for i, j := lo, hi-1; i < j; i, j = i+1, j-1 {
+		e1, e2 := x.a.Get(i), x.a.Get(j)
+		x.a.Set(i, e2)
+		x.a.Set(j, e1)
+	}
+}
+
+// AccessorInterface returns a sort.Interface for the elements of a,
+// ordered as determined by less, for use with sort.Sort, sort.Stable
+// and the other functions of the sort package. Unlike SortAccessor, it sorts
+// without any buffer, swapping elements with Get and Set, and calls Get twice
+// per comparison.
+func AccessorInterface[E any](a Accessor[E], less func(e1, e2 *E) bool) sort.Interface {
+	return &accessorInterface[E]{a, less}
+}
+
+type accessorInterface[E any] struct {
+	a    Accessor[E]
+	less func(e1, e2 *E) bool
+}
+
+func (x *accessorInterface[E]) Len() int { return x.a.Len() }
+
+func (x *accessorInterface[E]) Less(i, j int) bool {
+	e1, e2 := x.a.Get(i), x.a.Get(j)
+	return x.less(&e1, &e2)
+}
+
+func (x *accessorInterface[E]) Swap(i, j int) {
+	e1, e2 := x.a.Get(i), x.a.Get(j)
+	x.a.Set(i, e2)
+	x.a.Set(j, e1)
+}
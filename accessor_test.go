@@ -0,0 +1,131 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+// pagedInts is a collection of ints stored in fixed-size pages,
+// counting the calls to its accessors.
+type pagedInts struct {
+	pages    [][]int
+	n        int
+	gets     int
+	sets     int
+	pageSize int
+}
+
+func newPagedInts(s []int, pageSize int) *pagedInts {
+	p := &pagedInts{n: len(s), pageSize: pageSize}
+	for len(s) > 0 {
+		n := pageSize
+		if n > len(s) {
+			n = len(s)
+		}
+		p.pages = append(p.pages, append([]int(nil), s[:n]...))
+		s = s[n:]
+	}
+	return p
+}
+
+func (p *pagedInts) Len() int { return p.n }
+
+func (p *pagedInts) Get(i int) int {
+	p.gets++
+	return p.pages[i/p.pageSize][i%p.pageSize]
+}
+
+func (p *pagedInts) Set(i, x int) {
+	p.sets++
+	p.pages[i/p.pageSize][i%p.pageSize] = x
+}
+
+func (p *pagedInts) slice() []int {
+	var s []int
+	for _, page := range p.pages {
+		s = append(s, page...)
+	}
+	return s
+}
+
+func TestSortAccessor(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = r.Intn(100) - 50
+	}
+	less := func(i1, i2 *int) bool { return *i1 < *i2 }
+
+	for _, tt := range []struct {
+		name string
+		sort func(p *pagedInts)
+	}{
+		{"SortAccessor", func(p *pagedInts) { SortAccessor[int](p, less) }},
+		{"StableAccessor", func(p *pagedInts) { StableAccessor[int](p, less) }},
+		{"IntsAccessor", func(p *pagedInts) { IntsAccessor[int](p) }},
+		{"AccessorInterface", func(p *pagedInts) { sort.Sort(AccessorInterface[int](p, less)) }},
+	} {
+		p := newPagedInts(data, 64)
+		tt.sort(p)
+		if s := p.slice(); !sort.IntsAreSorted(s) || len(s) != len(data) {
+			t.Errorf("%s didn't sort", tt.name)
+		}
+		if tt.name != "AccessorInterface" && (p.gets != len(data) || p.sets != len(data)) {
+			t.Errorf("%s called Get %d and Set %d times, want %d each", tt.name, p.gets, p.sets, len(data))
+		}
+	}
+}
+
+func TestStableAccessor(t *testing.T) {
+	t.Parallel()
+
+	data := make([]int, 500)
+	for i := range data {
+		data[i] = (i%7)<<16 | i // Key in the high bits, original index in the low ones.
+	}
+	p := newPagedInts(data, 100)
+	StableAccessor[int](p, func(i1, i2 *int) bool { return *i1>>16 < *i2>>16 })
+	if s := p.slice(); !sort.IntsAreSorted(s) {
+		t.Error("StableAccessor didn't keep the order of equal elements")
+	}
+}
+
+func TestStableAccessorBuffer(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 7, 100, 1000, 3000} {
+		data := make([]int, n)
+		for i := range data {
+			data[i] = r.Intn(50)<<16 | i // Key in the high bits, original index in the low ones.
+		}
+		for _, buf := range []int{0, 1, 16, 100, n} {
+			p := newPagedInts(data, 64)
+			StableAccessorBuffer[int](p, func(i1, i2 *int) bool { return *i1>>16 < *i2>>16 }, buf)
+			if s := p.slice(); len(s) != n || !sort.IntsAreSorted(s) {
+				t.Errorf("StableAccessorBuffer of %d elements with a buffer of %d didn't sort stably", n, buf)
+			}
+		}
+	}
+
+	// A collection larger than the default buffer is merged through the accessor.
+	data := make([]int, 10000)
+	for i := range data {
+		data[i] = r.Intn(100)
+	}
+	p := newPagedInts(data, 256)
+	SortAccessor[int](p, func(i1, i2 *int) bool { return *i1 < *i2 })
+	if s := p.slice(); !sort.IntsAreSorted(s) {
+		t.Error("SortAccessor didn't sort a large collection")
+	}
+	p = newPagedInts(data, 256)
+	IntsAccessor[int](p)
+	if s := p.slice(); !sort.IntsAreSorted(s) {
+		t.Error("IntsAccessor didn't sort a large collection")
+	}
+}
@@ -0,0 +1,198 @@
+// This file implements detection of presorted input.
+
+package sorthelper
+
+import (
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Presortedness describes how close a slice is to being sorted,
+// as measured by MeasurePresortedness.
+type Presortedness struct {
+	// Len is the length of the slice.
+	Len int
+
+	// Runs is the number of maximal non-decreasing runs of the slice:
+	// 1 if the slice is sorted, up to Len if it is strictly decreasing.
+	Runs int
+
+	// DescendingRuns is the number of maximal non-increasing runs of the slice:
+	// 1 if the slice is sorted in decreasing order.
+	DescendingRuns int
+
+	// Inversions estimates the number of pairs of elements out of order, from 0
+	// for a sorted slice to Len*(Len-1)/2 for a strictly decreasing one.
+	// It is exact for slices of up to 64 elements and for sorted or strictly
+	// decreasing slices, and estimated from a sample of pairs otherwise.
+	Inversions int64
+}
+
+// Sorted reports whether the slice is sorted in increasing order.
+func (p Presortedness) Sorted() bool { return p.Runs <= 1 }
+
+// Reversed reports whether the slice is sorted in decreasing order.
+func (p Presortedness) Reversed() bool { return p.DescendingRuns <= 1 }
+
+// inversionSamples is the number of pairs of elements compared
+// to estimate the number of inversions of a slice.
+const inversionSamples = 1 << 10
+
+// MeasurePresortedness returns measures of how close the slice s is to being sorted
+// in increasing order. It takes O(len(s)) time.
+func MeasurePresortedness[E constraints.Ordered](s []E) Presortedness {
+	return MeasurePresortednessFunc(s, orderedLess[E])
+}
+
+// MeasurePresortednessFunc is like MeasurePresortedness but for the order defined
+// by the less function less, as used by Sorter.
+func MeasurePresortednessFunc[E any](s []E, less func(e1, e2 *E) bool) Presortedness {
+	p := Presortedness{Len: len(s)}
+	if len(s) == 0 {
+		return p
+	}
+	p.Runs, p.DescendingRuns = 1, 1
+	for i := 1; i < len(s); i++ {
+		switch {
+		case less(&s[i], &s[i-1]):
+			p.Runs++
+		case less(&s[i-1], &s[i]):
+			p.DescendingRuns++
+		}
+	}
+
+	n := int64(len(s))
+	pairs := n * (n - 1) / 2
+	switch {
+	case p.Runs == 1:
+		// No inversions.
+	case p.Runs == len(s):
+		p.Inversions = pairs
+	case pairs <= inversionSamples*2:
+		for j := 1; j < len(s); j++ {
+			for i := 0; i < j; i++ {
+				if less(&s[j], &s[i]) {
+					p.Inversions++
+				}
+			}
+		}
+	default:
+		// Count the inversions among pseudo-random pairs, seeded for reproducible results.
+		x := uint64(n)
+		var count int64
+		for k := 0; k < inversionSamples; {
+			x = splitmix64(x)
+			i, j := int(x%uint64(n)), int((x>>32)%uint64(n))
+			if i == j {
+				continue // Only pairs of distinct elements can be inversions.
+			}
+			if i > j {
+				i, j = j, i
+			}
+			if less(&s[j], &s[i]) {
+				count++
+			}
+			k++
+		}
+		// In floating point, as count * pairs overflows for large slices.
+		p.Inversions = int64(float64(count) / inversionSamples * float64(pairs))
+	}
+	return p
+}
+
+// splitmix64 returns the next state of the SplitMix64 pseudo-random generator after x.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	z := x
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// runLength returns the length of the run at the beginning of s, which is
+// non-decreasing, or strictly decreasing if desc is set.
+func runLength[E any](s []E, less func(e1, e2 *E) bool) (n int, desc bool) {
+	if len(s) < 2 {
+		return len(s), false
+	}
+	i := 2
+	if less(&s[1], &s[0]) {
+		for i < len(s) && less(&s[i], &s[i-1]) {
+			i++
+		}
+		return i, true
+	}
+	for i < len(s) && !less(&s[i], &s[i-1]) {
+		i++
+	}
+	return i, false
+}
+
+// runLengthOrdered is like runLength for the operator <, but a descending run
+// only needs to be non-increasing, as the order of equal elements doesn't matter
+// to the unstable sorts using it.
+func runLengthOrdered[E constraints.Ordered](s []E) (n int, desc bool) {
+	if len(s) < 2 {
+		return len(s), false
+	}
+	i := 2
+	if s[1] < s[0] {
+		for i < len(s) && !(s[i-1] < s[i]) {
+			i++
+		}
+		return i, true
+	}
+	for i < len(s) && !(s[i] < s[i-1]) {
+		i++
+	}
+	return i, false
+}
+
+// sortAdaptive sorts s as determined by less if it is made of a few runs,
+// and reports whether it did. It gives up as soon as the runs found are too
+// many and too short, leaving s to other algorithms: after O(log n) comparisons
+// on inputs without long presorted runs, but only after up to n comparisons
+// on inputs starting with a long run, such as a sorted prefix and a random tail.
+func sortAdaptive[E any](s []E, less func(e1, e2 *E) bool) bool {
+	return sortRuns(s, func(s []E) (int, bool) { return runLength(s, less) }, less)
+}
+
+// sortAdaptiveOrdered is like sortAdaptive for the operator <.
+func sortAdaptiveOrdered[E constraints.Ordered](s []E) bool {
+	return sortRuns(s, runLengthOrdered[E], orderedLess[E])
+}
+
+// minAverageRun is the average length of the runs of a slice
+// above which sortAdaptive merges them.
+const minAverageRun = 32
+
+// sortRuns sorts s if it is made of a few runs, as found by runLength: at most
+// log2(len(s)) runs, or runs of minAverageRun elements on average, such as
+// a sorted slice with a short unsorted tail. A single run is sorted in O(n) time,
+// reversing it if it is descending, without allocating. More runs are merged
+// with stableSort in O(n log r) time for r runs, with a scratch buffer of at most
+// the number of elements outside the longest run, and at most len(s)/2 elements.
+// It reports whether it sorted s, leaving other slices to the caller.
+func sortRuns[E any](s []E, runLength func(s []E) (int, bool), less func(e1, e2 *E) bool) bool {
+	limit := bits.Len(uint(len(s)))
+	runs, desc, longest := 0, false, 0
+	for i := 0; i < len(s); runs++ {
+		if runs >= limit && i < runs*minAverageRun {
+			return false
+		}
+		var n int
+		n, desc = runLength(s[i:])
+		if n > longest {
+			longest = n
+		}
+		i += n
+	}
+	switch {
+	case runs == 1 && desc:
+		reverse(s)
+	case runs > 1:
+		stableSort(s, less, len(s)-longest)
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package sorthelper_test
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestMeasurePresortedness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s    []int
+		want Presortedness
+	}{
+		{nil, Presortedness{}},
+		{[]int{1}, Presortedness{Len: 1, Runs: 1, DescendingRuns: 1}},
+		{[]int{1, 1, 1}, Presortedness{Len: 3, Runs: 1, DescendingRuns: 1}},
+		{[]int{1, 2, 2, 3}, Presortedness{Len: 4, Runs: 1, DescendingRuns: 3}},
+		{[]int{3, 2, 1}, Presortedness{Len: 3, Runs: 3, DescendingRuns: 1, Inversions: 3}},
+		{[]int{3, 3, 1}, Presortedness{Len: 3, Runs: 2, DescendingRuns: 1, Inversions: 2}},
+		{[]int{1, 3, 2, 4, 0}, Presortedness{Len: 5, Runs: 3, DescendingRuns: 3, Inversions: 5}},
+	}
+	for _, tt := range tests {
+		got := MeasurePresortedness(tt.s)
+		if got != tt.want {
+			t.Errorf("MeasurePresortedness(%v) = %+v, want %+v", tt.s, got, tt.want)
+		}
+		if got.Sorted() != sort.IntsAreSorted(tt.s) {
+			t.Errorf("MeasurePresortedness(%v).Sorted() = %t", tt.s, got.Sorted())
+		}
+	}
+
+	// A large slice with a known proportion of inversions: the two halves
+	// are sorted, and every element of the first is greater than those of the second.
+	const n = 10000
+	s := make([]int, n)
+	for i := range s {
+		s[i] = (i + n/2) % n
+	}
+	p := MeasurePresortedness(s)
+	if p.Runs != 2 || p.Sorted() || p.Reversed() {
+		t.Errorf("MeasurePresortedness(rotated) = %+v, want 2 runs", p)
+	}
+	want := float64(n/2) * float64(n/2)
+	if got := float64(p.Inversions); math.Abs(got-want) > want*0.2 {
+		t.Errorf("MeasurePresortedness(rotated).Inversions = %v, want about %v", got, want)
+	}
+
+	for i := range s {
+		s[i] = n - i
+	}
+	if p := MeasurePresortedness(s); !p.Reversed() || p.Inversions != n*(n-1)/2 {
+		t.Errorf("MeasurePresortedness(reversed) = %+v, want reversed with all the pairs inverted", p)
+	}
+}
+
+func TestSortPresorted(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{2, 100, 1000, 5000} {
+		for _, runs := range []int{1, 2, 3, 5} {
+			// Build runs ascending and descending in turn.
+			ints := make([]int, 0, n)
+			for k := 0; k < runs; k++ {
+				run := make([]int, n/runs)
+				for i := range run {
+					run[i] = r.Intn(n)
+				}
+				if k%2 == 0 {
+					sort.Ints(run)
+				} else {
+					sort.Sort(sort.Reverse(sort.IntSlice(run)))
+				}
+				ints = append(ints, run...)
+			}
+			floats := make([]float64, len(ints))
+			strs := make([]string, len(ints))
+			for i, x := range ints {
+				floats[i] = float64(x)
+				strs[i] = string(rune('a' + x%26))
+			}
+			if len(floats) > 0 {
+				floats[len(floats)/2] = math.NaN()
+			}
+			ints2 := append([]int(nil), ints...)
+
+			Ints(ints)
+			if !sort.IntsAreSorted(ints) {
+				t.Errorf("Ints didn't sort %d elements in %d runs", n, runs)
+			}
+			SliceSort(ints2)
+			if !sort.IntsAreSorted(ints2) {
+				t.Errorf("SliceSort didn't sort %d elements in %d runs", n, runs)
+			}
+			Float64s(floats)
+			if !Float64sAreSorted(floats) {
+				t.Errorf("Float64s didn't sort %d elements in %d runs", n, runs)
+			}
+			Strings(strs)
+			if !sort.StringsAreSorted(strs) {
+				t.Errorf("Strings didn't sort %d elements in %d runs", n, runs)
+			}
+		}
+	}
+}
+
+// TestSortPresortedAllocs is not parallel: it measures the memory allocated.
+func TestSortPresortedAllocs(t *testing.T) {
+	const n = 10000
+	r := rand.New(rand.NewSource(1))
+	s := make([]float64, n)
+	for i := range s[:n-16] {
+		s[i] = float64(i)
+	}
+	for i := n - 16; i < n; i++ {
+		s[i] = float64(r.Intn(n))
+	}
+
+	var m0, m1 runtime.MemStats
+	runtime.ReadMemStats(&m0)
+	Float64s(s)
+	runtime.ReadMemStats(&m1)
+	if !Float64sAreSorted(s) {
+		t.Fatal("Float64s didn't sort")
+	}
+	// The merge only needs a buffer for the unsorted tail.
+	if b := m1.TotalAlloc - m0.TotalAlloc; b > 1024 {
+		t.Errorf("Float64s of a sorted slice with a short tail allocated %d bytes", b)
+	}
+}
+
+func BenchmarkSortInt64K_NearlySorted(b *testing.B) {
+	// Appended logs: a sorted slice followed by a short unsorted tail.
+	unsorted := make([]int, 1<<16)
+	for i := range unsorted {
+		unsorted[i] = i
+	}
+	r := rand.New(rand.NewSource(1))
+	tail := unsorted[len(unsorted)-100:]
+	for i := range tail {
+		tail[i] = r.Intn(len(unsorted))
+	}
+	for _, bench := range [...]bench[int]{
+		{"sort.Ints", sort.Ints},
+		{"Ints", Ints[int]},
+		{"SliceSort", SliceSort[int]},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			data := make([]int, len(unsorted))
+			b.StopTimer()
+			for i := 0; i < b.N; i++ {
+				copy(data, unsorted)
+				b.StartTimer()
+				bench.f(data)
+				b.StopTimer()
+			}
+		})
+	}
+}
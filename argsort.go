@@ -0,0 +1,83 @@
+// This file implements sorting permutations.
+
+package sorthelper
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Argsort returns the permutation that sorts the slice s in increasing order,
+// with not-a-number (NaN) values ordered before other values, leaving s unchanged:
+// s[perm[0]], s[perm[1]], ... is sorted. The permutation can be applied
+// to s and to any slice parallel to it with ApplyPermutation.
+//
+// The sort is not guaranteed to be stable. For a stable sort, use ArgsortStable.
+func Argsort[E constraints.Ordered](s []E) []int {
+	perm := identity(len(s))
+	sort.Slice(perm, func(i, j int) bool { return isLess(s[perm[i]], s[perm[j]]) })
+	return perm
+}
+
+// ArgsortStable is like Argsort but keeps the original order of equal elements:
+// the indexes of equal elements are in increasing order.
+func ArgsortStable[E constraints.Ordered](s []E) []int {
+	perm := identity(len(s))
+	stableSort(perm, func(p, q *int) bool { return isLess(s[*p], s[*q]) }, -1)
+	return perm
+}
+
+// ArgsortFunc is like Argsort but orders the elements by the less function less,
+// as used by Sorter.
+func ArgsortFunc[E any](s []E, less func(e1, e2 *E) bool) []int {
+	perm := identity(len(s))
+	sort.Slice(perm, func(i, j int) bool { return less(&s[perm[i]], &s[perm[j]]) })
+	return perm
+}
+
+// ArgsortStableFunc is like ArgsortStable but orders the elements by the less function less,
+// as used by Sorter.
+func ArgsortStableFunc[E any](s []E, less func(e1, e2 *E) bool) []int {
+	perm := identity(len(s))
+	stableSort(perm, func(p, q *int) bool { return less(&s[*p], &s[*q]) }, -1)
+	return perm
+}
+
+// ApplyPermutation rearranges the slice s in place so that the new s[i] is the old s[perm[i]],
+// as needed to sort s with the permutation returned by Argsort.
+// It follows the cycles of perm, moving each element once, with a bitset
+// of len(s) bits to mark the elements placed. perm is only read, so that
+// it can be applied to several slices in turn, or concurrently.
+// ApplyPermutation panics if perm is not a permutation of the indexes of s.
+func ApplyPermutation[E any](s []E, perm []int) {
+	if len(perm) != len(s) {
+		panic("sorthelper: ApplyPermutation with a permutation of a different length")
+	}
+	checkPermutation(perm)
+	permute(s, perm)
+}
+
+// InversePermutation returns the inverse of the permutation perm: inv[perm[i]] == i.
+// For a permutation returned by Argsort, inv[i] is the position of s[i] in the sorted slice.
+// InversePermutation panics if perm is not a permutation of 0, 1, ..., len(perm)-1.
+func InversePermutation(perm []int) []int {
+	checkPermutation(perm)
+	inv := make([]int, len(perm))
+	for i, p := range perm {
+		inv[p] = i
+	}
+	return inv
+}
+
+// checkPermutation panics if perm is not a permutation of 0, 1, ..., len(perm)-1.
+// perm is only read, so that it can be shared by concurrent callers.
+func checkPermutation(perm []int) {
+	seen := newBitset(len(perm))
+	for _, p := range perm {
+		if p < 0 || p >= len(perm) || seen.has(p) {
+			panic("sorthelper: invalid permutation")
+		}
+		seen.add(p)
+	}
+}
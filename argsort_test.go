@@ -0,0 +1,139 @@
+package sorthelper_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestArgsort(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = r.Intn(100)
+	}
+	orig := append([]int(nil), data...)
+
+	for _, tt := range []struct {
+		name   string
+		perm   []int
+		stable bool
+	}{
+		{"Argsort", Argsort(data), false},
+		{"ArgsortStable", ArgsortStable(data), true},
+		{"ArgsortFunc", ArgsortFunc(data, func(i1, i2 *int) bool { return *i1 < *i2 }), false},
+		{"ArgsortStableFunc", ArgsortStableFunc(data, func(i1, i2 *int) bool { return *i1 < *i2 }), true},
+	} {
+		if !equalInts(data, orig) {
+			t.Fatalf("%s modified its input", tt.name)
+		}
+		if len(tt.perm) != len(data) {
+			t.Fatalf("%s returned %d indexes, want %d", tt.name, len(tt.perm), len(data))
+		}
+		for i := 1; i < len(tt.perm); i++ {
+			p, q := tt.perm[i-1], tt.perm[i]
+			if data[p] > data[q] || tt.stable && data[p] == data[q] && p > q {
+				t.Fatalf("%s: s[%d] = %d before s[%d] = %d", tt.name, p, data[p], q, data[q])
+			}
+		}
+	}
+
+	floats := []float64{3, math.NaN(), 1, 2}
+	if perm := Argsort(floats); perm[0] != 1 || perm[1] != 2 || perm[3] != 0 {
+		t.Errorf("Argsort(%v) = %v, want NaN first", floats, perm)
+	}
+}
+
+func TestApplyPermutation(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"carol", "alice", "dave", "bob"}
+	ages := []int{35, 30, 25, 40}
+	perm := Argsort(names)
+	inv := InversePermutation(perm)
+
+	ApplyPermutation(names, perm)
+	ApplyPermutation(ages, perm)
+	if want := []string{"alice", "bob", "carol", "dave"}; names[0] != want[0] || names[1] != want[1] || names[2] != want[2] || names[3] != want[3] {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+	if want := []int{30, 40, 35, 25}; !equalInts(ages, want) {
+		t.Errorf("ages = %v, want %v", ages, want)
+	}
+	if want := []int{1, 3, 0, 2}; !equalInts(perm, want) {
+		t.Errorf("perm = %v after ApplyPermutation, want it unchanged %v", perm, want)
+	}
+
+	// Applying the inverse permutation restores the original order.
+	ApplyPermutation(ages, inv)
+	if want := []int{35, 30, 25, 40}; !equalInts(ages, want) {
+		t.Errorf("ages = %v after applying the inverse, want %v", ages, want)
+	}
+}
+
+func TestInvalidPermutation(t *testing.T) {
+	t.Parallel()
+
+	for _, perm := range [][]int{{0, 0}, {1, 2}, {-1, 0}, {0, 2, 1, 2}} {
+		orig := append([]int(nil), perm...)
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("InversePermutation(%v) did not panic", perm)
+				}
+			}()
+			InversePermutation(perm)
+		}()
+		if !equalInts(perm, orig) {
+			t.Errorf("InversePermutation(%v) modified its argument to %v", orig, perm)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("ApplyPermutation with a short permutation did not panic")
+		}
+	}()
+	ApplyPermutation([]int{1, 2, 3}, []int{0, 1})
+}
+
+func TestApplyPermutationShared(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = r.Intn(100)
+	}
+	perm := Argsort(keys)
+	orig := append([]int(nil), perm...)
+
+	// Reorder several columns concurrently with the same permutation,
+	// which must only be read (checked with -race).
+	cols := make([][]int, 4)
+	done := make(chan struct{})
+	for c := range cols {
+		cols[c] = append([]int(nil), keys...)
+		go func(col []int) {
+			ApplyPermutation(col, perm)
+			InversePermutation(perm)
+			done <- struct{}{}
+		}(cols[c])
+	}
+	for range cols {
+		<-done
+	}
+
+	if !equalInts(perm, orig) {
+		t.Fatal("ApplyPermutation modified the permutation")
+	}
+	for _, col := range cols {
+		if !IntsAreSorted(col) {
+			t.Fatal("ApplyPermutation didn't sort a column")
+		}
+	}
+}
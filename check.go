@@ -0,0 +1,148 @@
+// This file implements checking of less functions.
+
+package sorthelper
+
+import (
+	"fmt"
+)
+
+// defaultCheckSamples is the number of triples checked by CheckComparator
+// when it isn't given a positive number of samples.
+const defaultCheckSamples = 1000
+
+// A ComparatorError describes how a less function fails to be a strict weak ordering,
+// as reported by CheckComparator.
+type ComparatorError[E any] struct {
+	// Property is the violated property: "irreflexivity", "asymmetry",
+	// "transitivity" or "transitivity of equivalence".
+	Property string
+
+	// Elems holds the elements showing the violation, in the order
+	// used by the message of the error.
+	Elems []E
+}
+
+func (e *ComparatorError[E]) Error() string {
+	var what string
+	switch e.Property {
+	case "irreflexivity":
+		what = fmt.Sprintf("less(a, a) for a = %v", e.Elems[0])
+	case "asymmetry":
+		what = fmt.Sprintf("less(a, b) and less(b, a) for a = %v, b = %v", e.Elems[0], e.Elems[1])
+	case "transitivity":
+		what = fmt.Sprintf("less(a, b) and less(b, c) but not less(a, c) for a = %v, b = %v, c = %v",
+			e.Elems[0], e.Elems[1], e.Elems[2])
+	default:
+		what = fmt.Sprintf("a equivalent to b and b equivalent to c but not to a for a = %v, b = %v, c = %v",
+			e.Elems[0], e.Elems[1], e.Elems[2])
+	}
+	return "sorthelper: less function violates " + e.Property + ": " + what
+}
+
+// CheckComparator checks that less is a strict weak ordering on the elements of s,
+// as required by the functions of this package: it is irreflexive, asymmetric
+// and transitive, and equivalence (neither element less than the other) is transitive.
+// It checks up to samples triples of elements of s, all of them if s is small enough,
+// and pseudo-random ones otherwise, seeded for reproducible results.
+// If samples <= 0, a default of 1000 triples is used.
+//
+// It returns a *ComparatorError[E] holding the offending elements
+// for the first violation found, and nil if none is found.
+//
+// When the package is built with the sorthelperdebug tag, the sort methods
+// of Sorter and MultiSorter check their less functions on a sample
+// of the slice before sorting it, and panic with the error of any violation.
+func CheckComparator[E any](s []E, less func(e1, e2 *E) bool, samples int) error {
+	if samples <= 0 {
+		samples = defaultCheckSamples
+	}
+
+	n := len(s)
+	if n == 0 {
+		return nil
+	}
+	if n <= samples && n*n <= samples/n {
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				for k := j; k < n; k++ {
+					if err := checkTriple(&s[i], &s[j], &s[k], less); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	x := uint64(n)
+	for ; samples > 0; samples-- {
+		var t [3]int
+		for m := range t {
+			x = splitmix64(x)
+			t[m] = int(x % uint64(n))
+		}
+		if err := checkTriple(&s[t[0]], &s[t[1]], &s[t[2]], less); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTriple checks the properties of a strict weak ordering
+// on every ordering of the elements a, b and c.
+func checkTriple[E any](a, b, c *E, less func(e1, e2 *E) bool) error {
+	p := [3]*E{a, b, c}
+	var m [3][3]bool // m[x][y] is less(p[x], p[y]).
+	for x := range p {
+		for y := range p {
+			m[x][y] = less(p[x], p[y])
+		}
+	}
+
+	fail := func(property string, xs ...int) error {
+		err := &ComparatorError[E]{Property: property}
+		for _, x := range xs {
+			err.Elems = append(err.Elems, *p[x])
+		}
+		return err
+	}
+
+	for x := range p {
+		if m[x][x] {
+			return fail("irreflexivity", x)
+		}
+	}
+	for x := range p {
+		for y := x + 1; y < len(p); y++ {
+			if m[x][y] && m[y][x] {
+				return fail("asymmetry", x, y)
+			}
+		}
+	}
+	equiv := func(x, y int) bool { return !m[x][y] && !m[y][x] }
+	for _, o := range [...][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}} {
+		x, y, z := o[0], o[1], o[2]
+		if m[x][y] && m[y][z] && !m[x][z] {
+			return fail("transitivity", x, y, z)
+		}
+		if equiv(x, y) && equiv(y, z) && !equiv(x, z) {
+			return fail("transitivity of equivalence", x, y, z)
+		}
+	}
+	return nil
+}
+
+// debugSamples is the number of triples checked before each sort
+// when the package is built with the sorthelperdebug tag.
+const debugSamples = 100
+
+// debugCheck panics with the error of CheckComparator if the package is built
+// with the sorthelperdebug tag and less isn't a strict weak ordering on s.
+func debugCheck[E any](s []E, less func(e1, e2 *E) bool) {
+	if !debug {
+		return
+	}
+	if err := CheckComparator(s, less, debugSamples); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,62 @@
+package sorthelper_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestCheckComparator(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	small := []float64{3, 1, 2, math.NaN(), 2}
+	large := make([]float64, 1000)
+	for i := range large {
+		large[i] = float64(r.Intn(100))
+	}
+	large[500] = math.NaN()
+
+	for _, tt := range []struct {
+		name     string
+		s        []float64
+		less     func(f1, f2 *float64) bool
+		property string // Empty when less is valid.
+	}{
+		{"Float64sLess", small, func(f1, f2 *float64) bool {
+			return *f1 < *f2 || (math.IsNaN(*f1) && !math.IsNaN(*f2))
+		}, ""},
+		{"LessOrEqual", small, func(f1, f2 *float64) bool { return *f1 <= *f2 }, "irreflexivity"},
+		{"NotEqual", small, func(f1, f2 *float64) bool { return *f1 != *f2 && !math.IsNaN(*f1) }, "asymmetry"},
+		{"Cyclic", []float64{0, 1, 2}, func(f1, f2 *float64) bool {
+			return int(*f2) == (int(*f1)+1)%3 // Rock, paper, scissors.
+		}, "transitivity"},
+		{"NaNSmall", small, func(f1, f2 *float64) bool { return *f1 < *f2 }, "transitivity of equivalence"},
+		{"NaNLarge", large, func(f1, f2 *float64) bool { return *f1 < *f2 }, "transitivity of equivalence"},
+		{"Empty", nil, func(f1, f2 *float64) bool { return true }, ""},
+	} {
+		err := CheckComparator(tt.s, tt.less, 0)
+		if tt.property == "" {
+			if err != nil {
+				t.Errorf("%s: CheckComparator = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+
+		var cerr *ComparatorError[float64]
+		if !errors.As(err, &cerr) {
+			t.Errorf("%s: CheckComparator = %v, want a *ComparatorError", tt.name, err)
+			continue
+		}
+		if cerr.Property != tt.property {
+			t.Errorf("%s: violated property %q, want %q (%v)", tt.name, cerr.Property, tt.property, err)
+		}
+		if prefix := "sorthelper: less function violates " + tt.property + ": "; len(err.Error()) < len(prefix) ||
+			err.Error()[:len(prefix)] != prefix {
+			t.Errorf("%s: error %q", tt.name, err)
+		}
+	}
+}
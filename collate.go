@@ -0,0 +1,59 @@
+// This file implements locale-aware string ordering.
+
+package sorthelper
+
+import (
+	"golang.org/x/text/collate"
+)
+
+// The functions in this file order strings with a collate.Collator, which compares
+// them according to the rules of a language and the strength chosen with its options:
+// for example collate.New(language.German, collate.IgnoreCase) ignores case
+// and collate.New(language.French, collate.Loose) also ignores accents and width.
+//
+// A collate.Collator is not safe for concurrent use, and neither are the
+// functions returned here, so a collator must not be shared by concurrent sorts.
+
+// CollatedStrings sorts a slice of strings in the order defined by the collator c.
+// The collation key of each string is computed once, which is much faster than
+// comparing the strings with the collator for large slices.
+// Strings that collate equal, such as "a" and "A" when case is ignored,
+// keep their original order.
+func CollatedStrings[E ~string](c *collate.Collator, x []E) {
+	key := CollationKey(c)
+	StableByKey(x, func(e *E) string { return key(string(*e)) })
+}
+
+// CollatedStringsAreSorted reports whether the slice x is sorted in the order
+// defined by the collator c.
+func CollatedStringsAreSorted[E ~string](c *collate.Collator, x []E) bool {
+	for i := len(x) - 1; i > 0; i-- {
+		if c.CompareString(string(x[i]), string(x[i-1])) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CollateCmp returns a comparison function ordering strings as the collator c,
+// for use with Sorter.OrderedByCmp, MultiSorter.OrderedByCmp and ByCmp.
+// It compares the strings on each call; to sort many strings, prefer keys
+// computed once with CollationKey.
+func CollateCmp[E ~string](c *collate.Collator) func(e1, e2 *E) int {
+	return func(e1, e2 *E) int { return c.CompareString(string(*e1), string(*e2)) }
+}
+
+// CollationKey returns a function computing the collation key of a string for
+// the collator c: keys compare with the operator < as the strings compare with c.
+// It is meant for SortByKey, StableByKey and ByKey, which compute each key once, as in
+//
+//	key := sorthelper.CollationKey(c)
+//	sorthelper.StableByKey(people, func(p *Person) string { return key(p.Name) })
+func CollationKey(c *collate.Collator) func(s string) string {
+	var buf collate.Buffer
+	return func(s string) string {
+		k := string(c.KeyFromString(&buf, s))
+		buf.Reset()
+		return k
+	}
+}
@@ -0,0 +1,84 @@
+package sorthelper_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+func TestCollatedStrings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		c    *collate.Collator
+		in   []string
+		want []string
+	}{
+		{
+			collate.New(language.English),
+			[]string{"zebra", "Émile", "apple", "Zoe", "emu"},
+			[]string{"apple", "Émile", "emu", "zebra", "Zoe"},
+		},
+		{
+			// Strings equal but for case keep their order.
+			collate.New(language.English, collate.IgnoreCase),
+			[]string{"b", "B", "a", "A"},
+			[]string{"a", "A", "b", "B"},
+		},
+		{
+			collate.New(language.English, collate.IgnoreCase, collate.IgnoreDiacritics),
+			[]string{"resume", "Résumé", "RESUME", "ресурс", "abc"},
+			[]string{"abc", "resume", "Résumé", "RESUME", "ресурс"},
+		},
+		{
+			// In Swedish, ä sorts after z.
+			collate.New(language.Swedish),
+			[]string{"äpple", "zebra", "apa"},
+			[]string{"apa", "zebra", "äpple"},
+		},
+	}
+	for _, tt := range tests {
+		data := append([]string(nil), tt.in...)
+		CollatedStrings(tt.c, data)
+		if !CollatedStringsAreSorted(tt.c, data) {
+			t.Errorf("CollatedStringsAreSorted(%q) = false after CollatedStrings", data)
+		}
+		for i := range data {
+			if data[i] != tt.want[i] {
+				t.Errorf("CollatedStrings(%q) = %q, want %q", tt.in, data, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestCollateCmp(t *testing.T) {
+	t.Parallel()
+
+	type person struct {
+		name string
+		age  int
+	}
+	people := []person{{"Ölaf", 3}, {"oscar", 1}, {"Olaf", 2}, {"zed", 4}}
+	c := collate.New(language.German, collate.Loose)
+	cmp := CollateCmp[string](c)
+	NewMultiSorter(people).StableByCmp(
+		func(p1, p2 *person) int { return cmp(&p1.name, &p2.name) },
+	)
+	want := []string{"Ölaf", "Olaf", "oscar", "zed"}
+	for i, p := range people {
+		if p.name != want[i] {
+			t.Fatalf("StableByCmp(CollateCmp) = %v, want names %q", people, want)
+		}
+	}
+
+	key := CollationKey(c)
+	StableByKey(people, func(p *person) string { return key(p.name) })
+	for i, p := range people {
+		if p.name != want[i] {
+			t.Fatalf("StableByKey(CollationKey) = %v, want names %q", people, want)
+		}
+	}
+}
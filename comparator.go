@@ -0,0 +1,156 @@
+// This file implements a declarative builder of comparison functions.
+
+package sorthelper
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// A Comparator orders elements of type E by a sequence of keys,
+// each compared only when all the keys before it are equal.
+// Comparators are built with By, ByPtr or ByCmp and refined with
+// their methods, each of which returns a new Comparator:
+//
+//	c := sorthelper.By(func(c *Change) string { return c.user }).Desc().
+//		ThenBy(sorthelper.By(func(c *Change) int { return c.lines }))
+//
+// The Compare and Less methods make a Comparator usable with Sorter and MultiSorter:
+//
+//	sorthelper.NewSorter(changes).OrderedBy(c.Less)
+//	sorthelper.NewMultiSorter(changes).OrderedByCmp(c.Compare)
+//
+// The zero Comparator considers all elements equal.
+type Comparator[E any] struct {
+	keys []comparatorKey[E]
+}
+
+// A comparatorKey is one key of a Comparator.
+type comparatorKey[E any] struct {
+	// cmp compares e1 and e2 and reports whether the result was
+	// decided by one of the keys being null, which Desc doesn't reverse.
+	cmp        func(e1, e2 *E, nullsFirst bool) (c int, null bool)
+	desc       bool
+	nullsFirst bool
+}
+
+// By returns a Comparator ordering elements in increasing order of the key computed by key,
+// with not-a-number (NaN) keys ordered before other keys.
+func By[E any, K constraints.Ordered](key func(*E) K) Comparator[E] {
+	return ByCmp(func(e1, e2 *E) int { return compareOrdered(key(e1), key(e2)) })
+}
+
+// ByPtr returns a Comparator ordering elements in increasing order of the key computed by key.
+// A nil key is null: nulls are ordered after other keys, or before them after NullsFirst.
+func ByPtr[E any, K constraints.Ordered](key func(*E) *K) Comparator[E] {
+	return Comparator[E]{keys: []comparatorKey[E]{{
+		cmp: func(e1, e2 *E, nullsFirst bool) (int, bool) {
+			k1, k2 := key(e1), key(e2)
+			switch {
+			case k1 == nil && k2 == nil:
+				return 0, true
+			case k1 == nil:
+				return nullOrder(nullsFirst), true
+			case k2 == nil:
+				return -nullOrder(nullsFirst), true
+			}
+			return compareOrdered(*k1, *k2), false
+		},
+	}}}
+}
+
+// nullOrder returns the comparison of a null key with a non-null one.
+func nullOrder(nullsFirst bool) int {
+	if nullsFirst {
+		return -1
+	}
+	return +1
+}
+
+// ByCmp returns a Comparator ordering elements by the comparison function cmp.
+func ByCmp[E any](cmp func(e1, e2 *E) int) Comparator[E] {
+	return Comparator[E]{keys: []comparatorKey[E]{{
+		cmp: func(e1, e2 *E, _ bool) (int, bool) { return cmp(e1, e2), false },
+	}}}
+}
+
+// withLast returns a copy of c with f applied to its last key.
+func (c Comparator[E]) withLast(f func(k *comparatorKey[E])) Comparator[E] {
+	if len(c.keys) == 0 {
+		return c
+	}
+	keys := append([]comparatorKey[E](nil), c.keys...)
+	f(&keys[len(keys)-1])
+	return Comparator[E]{keys: keys}
+}
+
+// Desc returns a Comparator that orders the last key of c in decreasing order.
+// The placement of null keys is not affected.
+func (c Comparator[E]) Desc() Comparator[E] {
+	return c.withLast(func(k *comparatorKey[E]) { k.desc = true })
+}
+
+// Asc returns a Comparator that orders the last key of c in increasing order,
+// undoing Desc.
+func (c Comparator[E]) Asc() Comparator[E] {
+	return c.withLast(func(k *comparatorKey[E]) { k.desc = false })
+}
+
+// NullsFirst returns a Comparator that orders null values of the last key of c
+// before other values. It only affects keys created by ByPtr.
+func (c Comparator[E]) NullsFirst() Comparator[E] {
+	return c.withLast(func(k *comparatorKey[E]) { k.nullsFirst = true })
+}
+
+// NullsLast returns a Comparator that orders null values of the last key of c
+// after other values, which is the default. It only affects keys created by ByPtr.
+func (c Comparator[E]) NullsLast() Comparator[E] {
+	return c.withLast(func(k *comparatorKey[E]) { k.nullsFirst = false })
+}
+
+// ThenBy returns a Comparator that breaks the ties of c using next.
+func (c Comparator[E]) ThenBy(next Comparator[E]) Comparator[E] {
+	keys := make([]comparatorKey[E], 0, len(c.keys)+len(next.keys))
+	keys = append(keys, c.keys...)
+	keys = append(keys, next.keys...)
+	return Comparator[E]{keys: keys}
+}
+
+// ThenCmp returns a Comparator that breaks the ties of c using the comparison function cmp.
+// A less function can be used as a tie-breaker through CmpFromLess.
+func (c Comparator[E]) ThenCmp(cmp func(e1, e2 *E) int) Comparator[E] {
+	return c.ThenBy(ByCmp(cmp))
+}
+
+// Compare returns a negative number when e1 is ordered before e2,
+// a positive number when e1 is ordered after e2 and zero otherwise.
+func (c Comparator[E]) Compare(e1, e2 *E) int {
+	for _, k := range c.keys {
+		r, null := k.cmp(e1, e2, k.nullsFirst)
+		if r != 0 {
+			if k.desc && !null {
+				r = -r
+			}
+			return r
+		}
+	}
+	return 0
+}
+
+// Less reports whether e1 is ordered before e2.
+func (c Comparator[E]) Less(e1, e2 *E) bool { return c.Compare(e1, e2) < 0 }
+
+// Search searches for x in the slice s, which must be sorted as determined by c,
+// and returns the index as specified by SearchFunc.
+func (c Comparator[E]) Search(s []E, x E) int {
+	return sort.Search(len(s), func(i int) bool { return c.Compare(&s[i], &x) >= 0 })
+}
+
+// Find searches for x in the slice s, which must be sorted as determined by c,
+// and returns the index as specified by SearchFunc, and reports whether
+// an element comparing equal to x is present at that index.
+func (c Comparator[E]) Find(s []E, x E) (int, bool) {
+	i := c.Search(s, x)
+	return i, i < len(s) && c.Compare(&s[i], &x) == 0
+}
@@ -0,0 +1,64 @@
+package sorthelper_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+type record struct {
+	name  string
+	score *int
+}
+
+func intPtr(i int) *int { return &i }
+
+func TestComparator(t *testing.T) {
+	t.Parallel()
+
+	name := By(func(r *record) string { return r.name })
+	score := ByPtr(func(r *record) *int { return r.score })
+
+	for _, tt := range []struct {
+		desc string
+		c    Comparator[record]
+		want []string
+	}{
+		{"name", name, []string{"a-", "a2", "a1", "b3", "b-", "b1"}},
+		{"name.Desc", name.Desc(), []string{"b3", "b-", "b1", "a-", "a2", "a1"}},
+		{"score", score.ThenBy(name), []string{"a1", "b1", "a2", "b3", "a-", "b-"}},
+		{"score.Desc", score.Desc().ThenBy(name), []string{"b3", "a2", "a1", "b1", "a-", "b-"}},
+		{"score.NullsFirst", score.NullsFirst().ThenBy(name.Desc()), []string{"b-", "a-", "b1", "a1", "a2", "b3"}},
+		{"score.Desc.NullsFirst", score.Desc().NullsFirst().ThenBy(name), []string{"a-", "b-", "b3", "a2", "a1", "b1"}},
+		{"name,score.Desc", name.ThenBy(score.Desc()), []string{"a2", "a1", "a-", "b3", "b1", "b-"}},
+		{"Asc", name.Desc().Asc(), []string{"a-", "a2", "a1", "b3", "b-", "b1"}},
+	} {
+		data := []record{
+			{"b", intPtr(3)},
+			{"a", nil},
+			{"a", intPtr(2)},
+			{"b", nil},
+			{"a", intPtr(1)},
+			{"b", intPtr(1)},
+		}
+		NewSorter(data).StableBy(tt.c.Less)
+
+		got := make([]string, len(data))
+		for i, r := range data {
+			got[i] = r.name + "-"
+			if r.score != nil {
+				got[i] = r.name + string(rune('0'+*r.score))
+			}
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.desc, got, tt.want)
+				break
+			}
+		}
+
+		if i := tt.c.Search(data, data[3]); tt.c.Compare(&data[i], &data[3]) != 0 {
+			t.Errorf("%s: Search(%v) = %d", tt.desc, data[3], i)
+		}
+	}
+}
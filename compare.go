@@ -0,0 +1,49 @@
+// This file implements adapters between the forms of comparison functions.
+
+package sorthelper
+
+import "golang.org/x/exp/constraints"
+
+// Compare returns
+//
+//	-1 if *e1 is less than *e2,
+//	 0 if *e1 equals *e2,
+//	+1 if *e1 is greater than *e2.
+//
+// Like the standard cmp.Compare, it orders not-a-number (NaN) values
+// before any others and considers them equal to each other.
+func Compare[E constraints.Ordered](e1, e2 *E) int { return compareOrdered(*e1, *e2) }
+
+// LessFromCmp returns a less function, as used by Sorter and MultiSorter,
+// reporting whether cmp(e1, e2) < 0.
+func LessFromCmp[E any](cmp func(e1, e2 *E) int) func(e1, e2 *E) bool {
+	return func(e1, e2 *E) bool { return cmp(e1, e2) < 0 }
+}
+
+// CmpFromLess returns a comparison function built from the less function less.
+// The comparison function calls less twice when the elements are not less
+// than each other, to tell equal elements from greater ones.
+func CmpFromLess[E any](less func(e1, e2 *E) bool) func(e1, e2 *E) int {
+	return func(e1, e2 *E) int {
+		switch {
+		case less(e1, e2):
+			return -1
+		case less(e2, e1):
+			return +1
+		}
+		return 0
+	}
+}
+
+// PtrCmp adapts a comparison function taking values,
+// such as cmp.Compare or strings.Compare,
+// to the pointer form used by Sorter and MultiSorter.
+func PtrCmp[E any](cmp func(a, b E) int) func(e1, e2 *E) int {
+	return func(e1, e2 *E) int { return cmp(*e1, *e2) }
+}
+
+// ValueCmp adapts a comparison function taking pointers to the value form
+// used by the standard slices package, as in slices.SortFunc.
+func ValueCmp[E any](cmp func(e1, e2 *E) int) func(a, b E) int {
+	return func(a, b E) int { return cmp(&a, &b) }
+}
@@ -0,0 +1,116 @@
+package sorthelper_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	nan := math.NaN()
+	for _, tt := range []struct {
+		a, b float64
+		want int
+	}{
+		{1, 2, -1},
+		{2, 1, +1},
+		{1, 1, 0},
+		{nan, 1, -1},
+		{1, nan, +1},
+		{nan, nan, 0},
+		{math.Inf(-1), nan, +1},
+	} {
+		if got := Compare(&tt.a, &tt.b); got != tt.want {
+			t.Errorf("Compare(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCmpAdapters(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b *int) bool { return *a < *b }
+	cmp := CmpFromLess(less)
+	for _, tt := range [][3]int{{1, 2, -1}, {2, 1, +1}, {3, 3, 0}} {
+		if got := cmp(&tt[0], &tt[1]); got != tt[2] {
+			t.Errorf("CmpFromLess(%d, %d) = %d, want %d", tt[0], tt[1], got, tt[2])
+		}
+		if got, want := LessFromCmp(cmp)(&tt[0], &tt[1]), tt[2] < 0; got != want {
+			t.Errorf("LessFromCmp(%d, %d) = %t, want %t", tt[0], tt[1], got, want)
+		}
+		if got := ValueCmp(PtrCmp(func(a, b int) int { return a - b }))(tt[0], tt[1]); got != tt[0]-tt[1] {
+			t.Errorf("ValueCmp(PtrCmp)(%d, %d) = %d, want %d", tt[0], tt[1], got, tt[0]-tt[1])
+		}
+	}
+}
+
+func TestMultiSorterOrderedByCmp(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]pair, 1000)
+	for i := range data {
+		data[i] = pair{r.Intn(10), r.Intn(100)}
+	}
+
+	var calls [2]int
+	key := func(p1, p2 *pair) int {
+		calls[0]++
+		return Compare(&p1.key, &p2.key)
+	}
+	index := func(p1, p2 *pair) int {
+		calls[1]++
+		return Compare(&p2.index, &p1.index)
+	}
+	ms := NewMultiSorter(data)
+	ms.OrderedByCmp(key, index)
+	if !sort.SliceIsSorted(data, func(i, j int) bool {
+		if data[i].key != data[j].key {
+			return data[i].key < data[j].key
+		}
+		return data[i].index > data[j].index
+	}) {
+		t.Errorf("OrderedByCmp didn't sort")
+	}
+
+	// Each Less call invokes every comparison function at most once.
+	calls = [2]int{}
+	for i := 1; i < len(data); i++ {
+		ms.Less(i-1, i)
+	}
+	if calls[0] != len(data)-1 || calls[1] > len(data)-1 {
+		t.Errorf("comparison calls = %v for %d Less calls", calls, len(data)-1)
+	}
+}
+
+func TestMultiSorterNoFunctions(t *testing.T) {
+	t.Parallel()
+
+	// Without any function, all the elements are equal.
+	for _, tt := range []struct {
+		name   string
+		sort   func(ms *MultiSorter[int])
+		stable bool
+	}{
+		{"OrderedBy", func(ms *MultiSorter[int]) { ms.OrderedBy() }, false},
+		{"StableBy", func(ms *MultiSorter[int]) { ms.StableBy() }, true},
+		{"OrderedByCmp", func(ms *MultiSorter[int]) { ms.OrderedByCmp() }, false},
+		{"StableByCmp", func(ms *MultiSorter[int]) { ms.StableByCmp() }, true},
+	} {
+		orig := []int{3, 1, 2, 5, 4}
+		data := append([]int(nil), orig...)
+		tt.sort(NewMultiSorter(data))
+		if tt.stable && !equalInts(data, orig) {
+			t.Errorf("%s with no functions = %v, want %v unchanged", tt.name, data, orig)
+		}
+		sort.Ints(data)
+		if !equalInts(data, []int{1, 2, 3, 4, 5}) {
+			t.Errorf("%s with no functions lost elements", tt.name)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// This file implements cancellable sorting.
+
+package sorthelper
+
+import (
+	"context"
+	"math/bits"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// checkInterval is the number of comparisons between two checks
+// of the context of a cancellable sort.
+const checkInterval = 1 << 10
+
+// canceled is the panic value unwinding a cancelled sort.
+type canceled struct{ err error }
+
+// A watcher counts the comparisons of a cancellable sort, checking its context
+// and reporting progress every checkInterval comparisons.
+type watcher struct {
+	ctx      context.Context
+	n        int // Comparisons done.
+	total    int // Estimated comparisons for the whole sort.
+	progress func(done, total int)
+}
+
+func newWatcher(ctx context.Context, n int, progress func(done, total int)) *watcher {
+	return &watcher{ctx: ctx, total: n * bits.Len(uint(n)), progress: progress}
+}
+
+// tick counts a comparison. It panics with a canceled value once the context is done.
+func (w *watcher) tick() {
+	w.n++
+	if w.n%checkInterval != 0 {
+		return
+	}
+	if err := w.ctx.Err(); err != nil {
+		panic(canceled{err})
+	}
+	if w.progress != nil {
+		// The estimate may fall short: keep done below total until the end.
+		done := w.n
+		if done >= w.total {
+			done = w.total - 1
+		}
+		w.progress(done, w.total)
+	}
+}
+
+// run calls sort, returning the error of the context if it is done before
+// sort returns. sort must only move elements by swapping them, so that
+// the slice is a permutation of its original contents whenever it is stopped.
+func (w *watcher) run(sort func()) (err error) {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			c, ok := r.(canceled)
+			if !ok {
+				panic(r)
+			}
+			err = c.err
+		}
+	}()
+
+	sort()
+	if w.progress != nil {
+		w.progress(w.total, w.total)
+	}
+	return nil
+}
+
+// watchedInterface is a sort.Interface whose comparisons are counted by a watcher.
+type watchedInterface struct {
+	sort.Interface
+	w *watcher
+}
+
+func (x *watchedInterface) Less(i, j int) bool {
+	x.w.tick()
+	return x.Interface.Less(i, j)
+}
+
+// SliceSortContext is like SliceSort, but it checks ctx periodically while sorting,
+// and returns ctx.Err() if ctx is done before the slice is sorted.
+// The slice is then left partially sorted, holding the same elements.
+// If progress is not nil, it is called as set by Sorter.OnProgress.
+func SliceSortContext[E constraints.Ordered](ctx context.Context, x []E, progress func(done, total int)) error {
+	w := newWatcher(ctx, len(x), progress)
+	return w.run(func() {
+		sort.Slice(x, func(i, j int) bool {
+			w.tick()
+			return x[i] < x[j]
+		})
+	})
+}
+
+// SliceStableContext is like SliceStable, but it checks ctx periodically while sorting,
+// and returns ctx.Err() if ctx is done before the slice is sorted.
+// The slice is then left partially sorted, holding the same elements.
+// If progress is not nil, it is called as set by Sorter.OnProgress.
+func SliceStableContext[E constraints.Ordered](ctx context.Context, x []E, progress func(done, total int)) error {
+	w := newWatcher(ctx, len(x), progress)
+	return w.run(func() {
+		sort.SliceStable(x, func(i, j int) bool {
+			w.tick()
+			return x[i] < x[j]
+		})
+	})
+}
+
+// OnProgress sets a function called periodically by the Context methods of s
+// while they sort, with the number of comparisons done and an estimate of
+// the total number of comparisons, then with done equal to total once sorted.
+// It returns s.
+func (s *Sorter[E]) OnProgress(progress func(done, total int)) *Sorter[E] {
+	s.progress = progress
+	return s
+}
+
+// OrderedByContext is like OrderedBy, but it checks ctx periodically while sorting,
+// and returns ctx.Err() if ctx is done before the slice is sorted.
+// The slice is then left partially sorted, holding the same elements.
+func (s *Sorter[E]) OrderedByContext(ctx context.Context, by func(e1, e2 *E) bool) error {
+	s.by = by
+	debugCheck(s.s, by)
+	w := newWatcher(ctx, len(s.s), s.progress)
+	var err error
+	s.obs.observe("Sorter.OrderedByContext", func() {
+		err = w.run(func() { sort.Sort(&watchedInterface{s.obs.wrap(s), w}) })
+	})
+	return err
+}
+
+// StableByContext is like StableBy, but it checks ctx periodically while sorting,
+// and returns ctx.Err() if ctx is done before the slice is sorted.
+// The slice is then left partially sorted, holding the same elements.
+// It sorts with sort.Stable, which only swaps elements, rather than the
+// merge sort of StableBy.
+func (s *Sorter[E]) StableByContext(ctx context.Context, by func(e1, e2 *E) bool) error {
+	s.by = by
+	debugCheck(s.s, by)
+	w := newWatcher(ctx, len(s.s), s.progress)
+	var err error
+	s.obs.observe("Sorter.StableByContext", func() {
+		err = w.run(func() { sort.Stable(&watchedInterface{s.obs.wrap(s), w}) })
+	})
+	return err
+}
+
+// OnProgress sets a function called periodically by the Context methods of ms
+// while they sort, with the number of comparisons done and an estimate of
+// the total number of comparisons, then with done equal to total once sorted.
+// It returns ms.
+func (ms *MultiSorter[E]) OnProgress(progress func(done, total int)) *MultiSorter[E] {
+	ms.progress = progress
+	return ms
+}
+
+// OrderedByContext is like OrderedBy, but it checks ctx periodically while sorting,
+// and returns ctx.Err() if ctx is done before the slice is sorted.
+// The slice is then left partially sorted, holding the same elements.
+func (ms *MultiSorter[E]) OrderedByContext(ctx context.Context, less ...func(e1, e2 *E) bool) error {
+	ms.less, ms.cmp = less, nil
+	debugCheck(ms.s, ms.lessElem)
+	w := newWatcher(ctx, len(ms.s), ms.progress)
+	var err error
+	ms.obs.observe("MultiSorter.OrderedByContext", func() {
+		err = w.run(func() { sort.Sort(&watchedInterface{ms.obs.wrap(ms), w}) })
+	})
+	return err
+}
+
+// StableByContext is like StableBy, but it checks ctx periodically while sorting,
+// and returns ctx.Err() if ctx is done before the slice is sorted.
+// The slice is then left partially sorted, holding the same elements.
+func (ms *MultiSorter[E]) StableByContext(ctx context.Context, less ...func(e1, e2 *E) bool) error {
+	ms.less, ms.cmp = less, nil
+	debugCheck(ms.s, ms.lessElem)
+	w := newWatcher(ctx, len(ms.s), ms.progress)
+	var err error
+	ms.obs.observe("MultiSorter.StableByContext", func() {
+		err = w.run(func() { sort.Stable(&watchedInterface{ms.obs.wrap(ms), w}) })
+	})
+	return err
+}
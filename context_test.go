@@ -0,0 +1,129 @@
+package sorthelper_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+// cancelAfter returns a less function on ints that cancels the context
+// after n comparisons.
+func cancelAfter(n int, cancel context.CancelFunc) func(i1, i2 *int) bool {
+	return func(i1, i2 *int) bool {
+		if n--; n == 0 {
+			cancel()
+		}
+		return *i1 < *i2
+	}
+}
+
+func TestSortContext(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]int, 10000)
+	for i := range data {
+		data[i] = r.Intn(1000)
+	}
+
+	for _, tt := range []struct {
+		name string
+		sort func(ctx context.Context, s []int, less func(i1, i2 *int) bool) error
+	}{
+		{"Sorter.OrderedByContext", func(ctx context.Context, s []int, less func(i1, i2 *int) bool) error {
+			return NewSorter(s).OrderedByContext(ctx, less)
+		}},
+		{"Sorter.StableByContext", func(ctx context.Context, s []int, less func(i1, i2 *int) bool) error {
+			return NewSorter(s).StableByContext(ctx, less)
+		}},
+		{"MultiSorter.OrderedByContext", func(ctx context.Context, s []int, less func(i1, i2 *int) bool) error {
+			return NewMultiSorter(s).OrderedByContext(ctx, less)
+		}},
+		{"MultiSorter.StableByContext", func(ctx context.Context, s []int, less func(i1, i2 *int) bool) error {
+			return NewMultiSorter(s).StableByContext(ctx, less)
+		}},
+	} {
+		s := append([]int(nil), data...)
+		if err := tt.sort(context.Background(), s, func(i1, i2 *int) bool { return *i1 < *i2 }); err != nil {
+			t.Errorf("%s = %v", tt.name, err)
+		}
+		if !sort.IntsAreSorted(s) {
+			t.Errorf("%s didn't sort", tt.name)
+		}
+
+		s = append([]int(nil), data...)
+		ctx, cancel := context.WithCancel(context.Background())
+		err := tt.sort(ctx, s, cancelAfter(5000, cancel))
+		cancel()
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s after cancel = %v, want %v", tt.name, err, context.Canceled)
+		}
+		if sort.IntsAreSorted(s) {
+			t.Errorf("%s sorted after cancel", tt.name)
+		}
+		// The slice holds the same elements.
+		sort.Ints(s)
+		want := append([]int(nil), data...)
+		sort.Ints(want)
+		if !equalInts(s, want) {
+			t.Errorf("%s lost elements after cancel", tt.name)
+		}
+	}
+}
+
+func TestSliceSortContext(t *testing.T) {
+	t.Parallel()
+
+	data := make([]int, 10000)
+	for i := range data {
+		data[i] = (i * 7919) % len(data)
+	}
+	for _, f := range []func(context.Context, []int, func(done, total int)) error{
+		SliceSortContext[int], SliceStableContext[int],
+	} {
+		s := append([]int(nil), data...)
+		var calls, last, total int
+		err := f(context.Background(), s, func(d, t int) {
+			calls++
+			last, total = d, t
+		})
+		if err != nil || !sort.IntsAreSorted(s) {
+			t.Errorf("sort = %v, sorted %t", err, sort.IntsAreSorted(s))
+		}
+		if calls < 2 || last != total || total == 0 {
+			t.Errorf("progress called %d times, last with %d/%d", calls, last, total)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		s = append([]int(nil), data...)
+		if err := f(ctx, s, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("sort with a cancelled context = %v, want %v", err, context.Canceled)
+		}
+		if !equalInts(s, data) {
+			t.Error("sort with a cancelled context modified the slice")
+		}
+	}
+}
+
+func TestSortContextProgress(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	s := r.Perm(10000)
+	var calls, last, total int
+	err := NewSorter(s).OnProgress(func(d, t int) {
+		calls++
+		last, total = d, t
+	}).OrderedByContext(context.Background(), func(i1, i2 *int) bool { return *i1 < *i2 })
+	if err != nil || !sort.IntsAreSorted(s) {
+		t.Fatalf("OrderedByContext = %v, sorted %t", err, sort.IntsAreSorted(s))
+	}
+	if calls < 2 || last != total || total == 0 {
+		t.Errorf("progress called %d times, last with %d/%d", calls, last, total)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build sorthelperdebug
+
+package sorthelper
+
+// debug enables the checking of less functions by Sorter and MultiSorter,
+// with the sorthelperdebug build tag.
+const debug = true
@@ -0,0 +1,32 @@
+//go:build sorthelperdebug
+
+package sorthelper_test
+
+import (
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestDebugCheck(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		sort func(s []int, less func(i1, i2 *int) bool)
+	}{
+		{"Sorter.OrderedBy", func(s []int, less func(i1, i2 *int) bool) { NewSorter(s).OrderedBy(less) }},
+		{"Sorter.StableBy", func(s []int, less func(i1, i2 *int) bool) { NewSorter(s).StableBy(less) }},
+		{"MultiSorter.OrderedBy", func(s []int, less func(i1, i2 *int) bool) { NewMultiSorter(s).OrderedBy(less) }},
+		{"MultiSorter.StableBy", func(s []int, less func(i1, i2 *int) bool) { NewMultiSorter(s).StableBy(less) }},
+	} {
+		func() {
+			defer func() {
+				if _, ok := recover().(*ComparatorError[int]); !ok {
+					t.Errorf("%s with an invalid less function did not panic with a *ComparatorError", tt.name)
+				}
+			}()
+			tt.sort([]int{3, 1, 2}, func(i1, i2 *int) bool { return *i1 <= *i2 })
+		}()
+	}
+}
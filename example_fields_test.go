@@ -0,0 +1,37 @@
+package sorthelper_test
+
+import (
+	"fmt"
+
+	"github.com/weiwenchen2022/sorthelper"
+)
+
+// This example demonstrates sorting by a spec such as one received in a query string.
+func ExampleSortByFields() {
+	type Commit struct {
+		User     string
+		Language string
+		Lines    int `sort:"delta"`
+	}
+
+	commits := []Commit{
+		{"gri", "Go", 100},
+		{"ken", "C", 150},
+		{"glenda", "Go", 200},
+		{"rsc", "Go", 200},
+		{"r", "Go", 100},
+		{"dmr", "C", 100},
+	}
+
+	if err := sorthelper.SortByFields(commits, "language,-delta,user"); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(commits)
+
+	err := sorthelper.SortByFields(commits, "language,-lines")
+	fmt.Println(err)
+
+	// Output:
+	// [{ken C 150} {dmr C 100} {glenda Go 200} {rsc Go 200} {gri Go 100} {r Go 100}]
+	// sorthelper: invalid field "lines" in sort spec "language,-lines": sorthelper_test.Commit has no field "lines"
+}
@@ -6,6 +6,7 @@ package sorthelper_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/weiwenchen2022/sorthelper"
 )
@@ -63,3 +64,17 @@ func Example_sortKeys() {
 	// By distance: [{Mercury 0.055 0.4} {Venus 0.815 0.7} {Earth 1 1} {Mars 0.107 1.5}]
 	// By decreasing distance: [{Mars 0.107 1.5} {Earth 1 1} {Venus 0.815 0.7} {Mercury 0.055 0.4}]
 }
+
+// This example demonstrates sorting by a key that is expensive to compute.
+// Each date is parsed once rather than on every comparison.
+func ExampleSortByKey() {
+	dates := []string{"Mar 3, 2021", "Jan 12, 2022", "Dec 24, 2020", "Jul 1, 2021"}
+	sorthelper.SortByKey(dates, func(s *string) int64 {
+		t, _ := time.Parse("Jan 2, 2006", *s)
+		return t.Unix()
+	})
+	fmt.Println(dates)
+
+	// Output:
+	// [Dec 24, 2020 Mar 3, 2021 Jul 1, 2021 Jan 12, 2022]
+}
@@ -70,3 +70,43 @@ func Example_sortMultiKeys() {
 	// By language,<lines: [{dmr C 100} {ken C 150} {r C 150} {gri Go 100} {r Go 100} {glenda Go 200} {ken Go 200} {rsc Go 200} {gri Smalltalk 80}]
 	// By language,<lines,user: [{dmr C 100} {ken C 150} {r C 150} {gri Go 100} {r Go 100} {glenda Go 200} {ken Go 200} {rsc Go 200} {gri Smalltalk 80}]
 }
+
+// ExampleMultiSorter_OrderedByCmp demonstrates chaining three-way comparison functions,
+// each of which is evaluated at most once per key for every pair of elements compared.
+func ExampleMultiSorter_OrderedByCmp() {
+	language := func(c1, c2 *Change) int {
+		return sorthelper.Compare(&c1.language, &c2.language)
+	}
+	decreasingLines := func(c1, c2 *Change) int {
+		return sorthelper.Compare(&c2.lines, &c1.lines) // Note: swapped arguments order downwards.
+	}
+	user := func(c1, c2 *Change) int {
+		return sorthelper.Compare(&c1.user, &c2.user)
+	}
+
+	sorthelper.NewMultiSorter(changes).OrderedByCmp(language, decreasingLines, user)
+	fmt.Println("By language,>lines,user:", changes)
+
+	// Output:
+	// By language,>lines,user: [{ken C 150} {r C 150} {dmr C 100} {glenda Go 200} {ken Go 200} {rsc Go 200} {gri Go 100} {r Go 100} {gri Smalltalk 80}]
+}
+
+// ExampleBy demonstrates building a comparison function for the Change structure
+// declaratively, one field at a time.
+func ExampleBy() {
+	byLanguage := sorthelper.By(func(c *Change) string { return c.language })
+	byLines := sorthelper.By(func(c *Change) int { return c.lines })
+	byUser := sorthelper.By(func(c *Change) string { return c.user })
+
+	c := byLanguage.ThenBy(byLines.Desc()).ThenBy(byUser)
+	sorthelper.NewSorter(changes).OrderedBy(c.Less)
+	fmt.Println("By language,>lines,user:", changes)
+
+	c = byUser.Desc().ThenBy(byLines)
+	sorthelper.NewMultiSorter(changes).OrderedByCmp(c.Compare)
+	fmt.Println("By >user,<lines:", changes)
+
+	// Output:
+	// By language,>lines,user: [{ken C 150} {r C 150} {dmr C 100} {glenda Go 200} {ken Go 200} {rsc Go 200} {gri Go 100} {r Go 100} {gri Smalltalk 80}]
+	// By >user,<lines: [{rsc Go 200} {r Go 100} {r C 150} {ken C 150} {ken Go 200} {gri Smalltalk 80} {gri Go 100} {glenda Go 200} {dmr C 100}]
+}
@@ -60,3 +60,20 @@ func ExampleSearchInts() {
 	// found 2 at index 1 in [1 2 3 4 6 7 8]
 	// 5 not found, can be inserted at index 4 in [1 2 3 4 6 7 8]
 }
+
+// This example demonstrates finding all the occurrences of a value
+// in a list sorted in ascending order.
+func ExampleEqualRange() {
+	a := []int{1, 2, 2, 2, 3, 5, 5, 8}
+
+	lo, hi := sorthelper.EqualRange(a, 2)
+	fmt.Printf("2 occurs %d times: %v\n", hi-lo, a[lo:hi])
+
+	if i, found := sorthelper.Find(a, 4); !found {
+		fmt.Printf("4 not found, can be inserted at index %d\n", i)
+	}
+
+	// Output:
+	// 2 occurs 3 times: [2 2 2]
+	// 4 not found, can be inserted at index 5
+}
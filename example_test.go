@@ -76,3 +76,66 @@ func ExampleStrings() {
 	// Output:
 	// [Alpha Bravo Delta Go Gopher Grin]
 }
+
+func ExampleMergeSorted() {
+	a := []int{1, 4, 7}
+	b := []int{2, 4, 8}
+	c := []int{0, 9}
+	fmt.Println(sorthelper.MergeSorted(a, b, c))
+	// Output: [0 1 2 4 4 7 8 9]
+}
+
+// This example demonstrates a priority queue whose priorities change
+// while the elements are queued.
+func ExamplePriorityQueue() {
+	type task struct {
+		name     string
+		deadline int
+	}
+	pq := sorthelper.NewPriorityQueueFunc(func(t1, t2 *task) bool { return t1.deadline < t2.deadline })
+
+	build := pq.Push(task{"build", 30})
+	pq.Push(task{"test", 20})
+	pq.Push(task{"deploy", 40})
+
+	// The build is now urgent.
+	pq.Update(build, task{"build", 10})
+
+	for pq.Len() > 0 {
+		fmt.Println(pq.Pop().name)
+	}
+	// Output:
+	// build
+	// test
+	// deploy
+}
+
+func ExampleNaturalStrings() {
+	files := []string{"img12.png", "img10.png", "IMG2.png", "img2.png", "img1.png"}
+	sorthelper.Strings(files)
+	fmt.Println(files)
+
+	sorthelper.NaturalStrings(files)
+	fmt.Println(files)
+
+	// Output:
+	// [IMG2.png img1.png img10.png img12.png img2.png]
+	// [IMG2.png img1.png img2.png img10.png img12.png]
+}
+
+func ExampleArgsort() {
+	names := []string{"carol", "alice", "bob"}
+	ages := []int{35, 30, 40}
+
+	perm := sorthelper.Argsort(names)
+	fmt.Println(perm)
+
+	// Reorder both columns the same way.
+	sorthelper.ApplyPermutation(names, perm)
+	sorthelper.ApplyPermutation(ages, perm)
+	fmt.Println(names, ages)
+
+	// Output:
+	// [1 2 0]
+	// [alice bob carol] [30 40 35]
+}
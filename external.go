@@ -0,0 +1,309 @@
+// This file implements external merge sort for data sets larger than memory.
+
+package sorthelper
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"reflect"
+	"unsafe"
+)
+
+// An Iterator yields a sequence of elements.
+// Next returns the next element, or io.EOF when the sequence is exhausted.
+type Iterator[E any] interface {
+	Next() (E, error)
+}
+
+// SliceIterator returns an Iterator yielding the elements of s in order.
+func SliceIterator[E any](s []E) Iterator[E] {
+	return &sliceIterator[E]{s: s}
+}
+
+type sliceIterator[E any] struct {
+	s []E
+}
+
+func (it *sliceIterator[E]) Next() (E, error) {
+	if len(it.s) == 0 {
+		var zero E
+		return zero, io.EOF
+	}
+	e := it.s[0]
+	it.s = it.s[1:]
+	return e, nil
+}
+
+// A Codec encodes elements to and decodes elements from a byte stream.
+// Decode returns io.EOF when the stream ends cleanly before an element,
+// and io.ErrUnexpectedEOF when it ends in the middle of one.
+type Codec[E any] interface {
+	Encode(w *bufio.Writer, e E) error
+	Decode(r *bufio.Reader) (E, error)
+}
+
+// LineCodec is a Codec for strings stored one per line.
+// Encoded strings must not contain newlines.
+// On decoding, a trailing carriage return is kept and a final line
+// without a newline is accepted.
+type LineCodec[E ~string] struct{}
+
+func (LineCodec[E]) Encode(w *bufio.Writer, e E) error {
+	w.WriteString(string(e))
+	return w.WriteByte('\n')
+}
+
+func (LineCodec[E]) Decode(r *bufio.Reader) (E, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if line[len(line)-1] == '\n' {
+		line = line[:len(line)-1]
+	}
+	return E(line), nil
+}
+
+// BinaryCodec is a Codec for fixed-size values, such as integers, floats
+// and structs made of them, stored with encoding/binary in the given byte order.
+type BinaryCodec[E any] struct {
+	Order binary.ByteOrder
+}
+
+func (c BinaryCodec[E]) Encode(w *bufio.Writer, e E) error {
+	return binary.Write(w, c.Order, e)
+}
+
+func (c BinaryCodec[E]) Decode(r *bufio.Reader) (E, error) {
+	var e E
+	err := binary.Read(r, c.Order, &e)
+	return e, err
+}
+
+// ExternalSorter sorts sequences of elements that don't fit in memory.
+// It reads the input in runs that fit in the memory budget, sorts each run
+// in memory with Sorter and spills it to a temporary file, then merges
+// the runs into the output, in several passes if there are more runs
+// than can be merged at once.
+type ExternalSorter[E any] struct {
+	// Less defines the sort order, as for Sorter.
+	Less func(e1, e2 *E) bool
+
+	// Codec encodes the elements in the temporary files and in the output,
+	// and decodes them from the input of SortReader.
+	Codec Codec[E]
+
+	// MemoryLimit is the approximate number of bytes of elements held in memory
+	// at once. If zero, 64 MiB is used.
+	MemoryLimit int
+
+	// SizeOf estimates the number of bytes used by an element, including
+	// any memory it references. If nil, the size of E itself is used,
+	// plus the length of the string for types with an underlying string type.
+	// Sizes below 1 byte are counted as 1, so that runs stay bounded
+	// by MemoryLimit for zero-sized types.
+	SizeOf func(e *E) int
+
+	// MaxFanIn is the maximum number of runs merged at once, each of which
+	// holds an open file. More runs are merged in several passes,
+	// through intermediate temporary files. If zero, 64 is used.
+	MaxFanIn int
+
+	// TempDir is the directory for temporary files. If empty, os.TempDir is used.
+	TempDir string
+
+	// Stable requests that equal elements keep their original order.
+	Stable bool
+}
+
+const (
+	defaultMemoryLimit = 64 << 20
+	defaultMaxFanIn    = 64
+)
+
+// Sort reads all the elements from src, sorts them and writes them
+// to dst encoded with the Codec.
+// The temporary files are removed before Sort returns.
+func (es *ExternalSorter[E]) Sort(dst io.Writer, src Iterator[E]) (err error) {
+	var runs []string // Names of the temporary files holding the runs, in order.
+	defer func() {
+		for _, name := range runs {
+			os.Remove(name)
+		}
+	}()
+
+	limit := es.MemoryLimit
+	if limit <= 0 {
+		limit = defaultMemoryLimit
+	}
+	fanIn := es.MaxFanIn
+	if fanIn <= 0 {
+		fanIn = defaultMaxFanIn
+	}
+	if fanIn < 2 {
+		fanIn = 2
+	}
+
+	var run []E
+	for done := false; !done; {
+		run, done, err = es.readRun(run[:0], src, limit)
+		if err != nil {
+			return err
+		}
+		es.sortRun(run)
+
+		if done && len(runs) == 0 {
+			// Everything fit in memory: no need for temporary files.
+			return es.writeAll(dst, SliceIterator(run))
+		}
+		if len(run) == 0 {
+			break
+		}
+
+		name, err := es.spill(SliceIterator(run))
+		if name != "" {
+			runs = append(runs, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	run = nil
+
+	// Merge consecutive groups of runs, which keeps equal elements
+	// in their original order, until they can all be merged at once.
+	for len(runs) > fanIn {
+		var next []string
+		for len(runs) > 0 {
+			n := fanIn
+			if n > len(runs) {
+				n = len(runs)
+			}
+			name, err := es.mergeRuns(runs[:n], es.spill)
+			if name != "" {
+				next = append(next, name)
+			}
+			if err != nil {
+				runs = append(next, runs...)
+				return err
+			}
+			for _, name := range runs[:n] {
+				os.Remove(name)
+			}
+			runs = runs[n:]
+		}
+		runs = next
+	}
+	_, err = es.mergeRuns(runs, func(src Iterator[E]) (string, error) {
+		return "", es.writeAll(dst, src)
+	})
+	return err
+}
+
+// spill writes the elements of src to a new temporary file, which it closes,
+// and returns its name. The name is returned along with any error once
+// the file is created, so that the caller can remove it.
+func (es *ExternalSorter[E]) spill(src Iterator[E]) (string, error) {
+	f, err := os.CreateTemp(es.TempDir, "sorthelper-run-*")
+	if err != nil {
+		return "", err
+	}
+	err = es.writeAll(f, src)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return f.Name(), err
+}
+
+// mergeRuns opens the run files named runs and passes the merge
+// of their elements to write, returning its results.
+func (es *ExternalSorter[E]) mergeRuns(runs []string, write func(src Iterator[E]) (string, error)) (string, error) {
+	sources := make([]Iterator[E], 0, len(runs))
+	for _, name := range runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		sources = append(sources, &decodeIterator[E]{r: bufio.NewReader(f), codec: es.Codec})
+	}
+	return write(newMergeIterator(es.Less, sources, true, false))
+}
+
+// SortReader decodes the elements from src with the Codec, sorts them
+// and writes them to dst encoded with the Codec.
+func (es *ExternalSorter[E]) SortReader(dst io.Writer, src io.Reader) error {
+	return es.Sort(dst, &decodeIterator[E]{r: bufio.NewReader(src), codec: es.Codec})
+}
+
+// readRun appends elements from src to run until the memory limit is reached.
+// It reports whether src is exhausted.
+func (es *ExternalSorter[E]) readRun(run []E, src Iterator[E], limit int) ([]E, bool, error) {
+	size := 0
+	for size < limit {
+		e, err := src.Next()
+		if err == io.EOF {
+			return run, true, nil
+		}
+		if err != nil {
+			return run, false, err
+		}
+		run = append(run, e)
+		if n := es.sizeOf(&run[len(run)-1]); n > 1 {
+			size += n
+		} else {
+			size++
+		}
+	}
+	return run, false, nil
+}
+
+func (es *ExternalSorter[E]) sizeOf(e *E) int {
+	if es.SizeOf != nil {
+		return es.SizeOf(e)
+	}
+	size := int(unsafe.Sizeof(*e))
+	if v := reflect.ValueOf(e).Elem(); v.Kind() == reflect.String {
+		size += v.Len()
+	}
+	return size
+}
+
+func (es *ExternalSorter[E]) sortRun(run []E) {
+	if es.Stable {
+		NewSorter(run).StableBy(es.Less)
+	} else {
+		NewSorter(run).OrderedBy(es.Less)
+	}
+}
+
+// writeAll encodes all the elements of src to w.
+func (es *ExternalSorter[E]) writeAll(w io.Writer, src Iterator[E]) error {
+	bw := bufio.NewWriter(w)
+	for {
+		e, err := src.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := es.Codec.Encode(bw, e); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// decodeIterator is an Iterator decoding elements from a reader.
+type decodeIterator[E any] struct {
+	r     *bufio.Reader
+	codec Codec[E]
+}
+
+func (it *decodeIterator[E]) Next() (E, error) { return it.codec.Decode(it.r) }
@@ -0,0 +1,255 @@
+package sorthelper_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestExternalSorterLines(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	var in bytes.Buffer
+	want := make([]string, 10000)
+	for i := range want {
+		want[i] = strconv.Itoa(r.Intn(1e6))
+		in.WriteString(want[i] + "\n")
+	}
+	sort.Strings(want)
+
+	dir := t.TempDir()
+	es := &ExternalSorter[string]{
+		Less:        func(s1, s2 *string) bool { return *s1 < *s2 },
+		Codec:       LineCodec[string]{},
+		MemoryLimit: 16 << 10,
+		TempDir:     dir,
+	}
+	var out bytes.Buffer
+	if err := es.SortReader(&out, &in); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := bufio.NewScanner(&out)
+	var got []string
+	for sc.Scan() {
+		got = append(got, sc.Text())
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d temporary files left behind", len(files))
+	}
+}
+
+type extRecord struct {
+	Key, Index int32
+}
+
+func TestExternalSorterStable(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]extRecord, 5000)
+	for i := range data {
+		data[i] = extRecord{int32(r.Intn(20)), int32(i)}
+	}
+
+	for _, limit := range []int{0, 1 << 10, 1 << 12} {
+		es := &ExternalSorter[extRecord]{
+			Less:        func(r1, r2 *extRecord) bool { return r1.Key < r2.Key },
+			Codec:       BinaryCodec[extRecord]{Order: binary.LittleEndian},
+			MemoryLimit: limit,
+			TempDir:     t.TempDir(),
+			Stable:      true,
+		}
+		var out bytes.Buffer
+		if err := es.Sort(&out, SliceIterator(data)); err != nil {
+			t.Fatal(err)
+		}
+
+		got := make([]extRecord, len(data))
+		if err := binary.Read(&out, binary.LittleEndian, got); err != nil {
+			t.Fatal(err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("limit=%d: %d trailing bytes", limit, out.Len())
+		}
+		if !sort.SliceIsSorted(got, func(i, j int) bool {
+			if got[i].Key != got[j].Key {
+				return got[i].Key < got[j].Key
+			}
+			return got[i].Index < got[j].Index
+		}) {
+			t.Errorf("limit=%d: output is not stably sorted", limit)
+		}
+	}
+}
+
+type failingIterator struct {
+	n   int
+	err error
+}
+
+func (it *failingIterator) Next() (int64, error) {
+	if it.n == 0 {
+		return 0, it.err
+	}
+	it.n--
+	return int64(it.n), nil
+}
+
+func TestExternalSorterError(t *testing.T) {
+	t.Parallel()
+
+	errRead := errors.New("read failed")
+	dir := t.TempDir()
+	es := &ExternalSorter[int64]{
+		Less:        func(i1, i2 *int64) bool { return *i1 < *i2 },
+		Codec:       BinaryCodec[int64]{Order: binary.BigEndian},
+		MemoryLimit: 100,
+		TempDir:     dir,
+	}
+	if err := es.Sort(io.Discard, &failingIterator{100, errRead}); err != errRead {
+		t.Errorf("Sort = %v, want %v", err, errRead)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d temporary files left behind", len(files))
+	}
+
+	in := bytes.NewReader([]byte{1, 2, 3})
+	if err := es.SortReader(io.Discard, in); err != io.ErrUnexpectedEOF {
+		t.Errorf("SortReader of truncated input = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+// countingIterator yields the elements of s, and counts the files in dir
+// when it is exhausted.
+type countingIterator[E any] struct {
+	s     []E
+	dir   string
+	files int
+}
+
+func (it *countingIterator[E]) Next() (E, error) {
+	if len(it.s) == 0 {
+		files, _ := os.ReadDir(it.dir)
+		it.files = len(files)
+		var zero E
+		return zero, io.EOF
+	}
+	e := it.s[0]
+	it.s = it.s[1:]
+	return e, nil
+}
+
+func TestExternalSorterFanIn(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]extRecord, 5000)
+	for i := range data {
+		data[i] = extRecord{int32(r.Intn(20)), int32(i)}
+	}
+
+	for _, tt := range []struct {
+		fanIn  int
+		sizeOf func(*extRecord) int
+	}{
+		{2, nil},
+		{3, nil},
+		{0, nil},
+		{5, func(*extRecord) int { return 0 }}, // Counted as 1 byte each.
+	} {
+		dir := t.TempDir()
+		es := &ExternalSorter[extRecord]{
+			Less:        func(r1, r2 *extRecord) bool { return r1.Key < r2.Key },
+			Codec:       BinaryCodec[extRecord]{Order: binary.LittleEndian},
+			MemoryLimit: 256,
+			SizeOf:      tt.sizeOf,
+			MaxFanIn:    tt.fanIn,
+			TempDir:     dir,
+			Stable:      true,
+		}
+		src := &countingIterator[extRecord]{s: data, dir: dir}
+		var out bytes.Buffer
+		if err := es.Sort(&out, src); err != nil {
+			t.Fatal(err)
+		}
+		if src.files < 2 {
+			t.Errorf("MaxFanIn=%d: %d runs spilled, want several", tt.fanIn, src.files)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("MaxFanIn=%d: %d temporary files left behind", tt.fanIn, len(files))
+		}
+
+		got := make([]extRecord, len(data))
+		if err := binary.Read(&out, binary.LittleEndian, got); err != nil {
+			t.Fatal(err)
+		}
+		if !sort.SliceIsSorted(got, func(i, j int) bool {
+			if got[i].Key != got[j].Key {
+				return got[i].Key < got[j].Key
+			}
+			return got[i].Index < got[j].Index
+		}) {
+			t.Errorf("MaxFanIn=%d: output is not stably sorted", tt.fanIn)
+		}
+	}
+}
+
+type extLine string
+
+func TestExternalSorterNamedStrings(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]extLine, 20)
+	for i := range data {
+		data[i] = extLine(bytes.Repeat([]byte(strconv.Itoa(r.Intn(10))), 300))
+	}
+
+	// Each line counts for more than 300 bytes, so that runs hold
+	// 4 lines and the 20 lines are spilled in 5 runs.
+	dir := t.TempDir()
+	es := &ExternalSorter[extLine]{
+		Less:        func(s1, s2 *extLine) bool { return *s1 < *s2 },
+		Codec:       LineCodec[extLine]{},
+		MemoryLimit: 1 << 10,
+		MaxFanIn:    64,
+		TempDir:     dir,
+	}
+	src := &countingIterator[extLine]{s: data, dir: dir}
+	var out bytes.Buffer
+	if err := es.Sort(&out, src); err != nil {
+		t.Fatal(err)
+	}
+	if src.files != 5 {
+		t.Errorf("%d runs spilled, want 5", src.files)
+	}
+
+	sc := bufio.NewScanner(&out)
+	var got []string
+	for sc.Scan() {
+		got = append(got, sc.Text())
+	}
+	if len(got) != len(data) || !sort.StringsAreSorted(got) {
+		t.Errorf("Sort wrote %d lines, sorted %t, want %d sorted lines", len(got), sort.StringsAreSorted(got), len(data))
+	}
+}
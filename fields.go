@@ -0,0 +1,285 @@
+// This file implements sorting slices of structs by field names resolved at run time.
+
+package sorthelper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// A fieldKey is one compiled term of a sort spec.
+type fieldKey struct {
+	index []int // Field indexes from the element to the key, for reflect.Value.FieldByIndex.
+	cmp   func(v1, v2 reflect.Value) int
+	desc  bool
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SortByFields sorts the slice s according to spec, a comma-separated
+// list of field paths such as "language,-lines,user".
+// s must be a slice of structs or of pointers to structs, or a pointer to such a slice.
+//
+// Each path names an exported field of the struct, or a field of a nested struct
+// with a dotted path like "author.name". Names match the `sort:"name"` tag of a
+// field or, in the absence of a tag, its name ignoring case; a field tagged
+// `sort:"-"` cannot be sorted on. Fields of embedded structs are promoted,
+// including the exported fields of unexported embedded structs.
+// Fields must have a boolean, integer, floating-point or string kind, or be a time.Time,
+// possibly through pointers. A prefix of '-' sorts a field in decreasing order
+// and a prefix of '+' in increasing order, the default.
+// Nil pointers along a path are ordered after all other values.
+//
+// The spec is validated and compiled before sorting, so an invalid spec
+// reports an error and leaves s untouched.
+// The sort is not guaranteed to be stable. For a stable sort, use StableByFields.
+func SortByFields(s any, spec string) error {
+	return sortByFields(s, spec, false)
+}
+
+// StableByFields sorts the slice s according to spec, as specified by SortByFields,
+// while keeping the original order of equal elements.
+func StableByFields(s any, spec string) error {
+	return sortByFields(s, spec, true)
+}
+
+func sortByFields(s any, spec string, stable bool) error {
+	v := reflect.ValueOf(s)
+	if v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.Slice {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("sorthelper: cannot sort by fields a value of type %T, want a slice", s)
+	}
+
+	keys, err := compileFields(v.Type().Elem(), spec)
+	if err != nil {
+		return err
+	}
+
+	less := func(i, j int) bool {
+		e1, e2 := v.Index(i), v.Index(j)
+		for _, k := range keys {
+			f1, ok1 := fieldByIndex(e1, k.index)
+			f2, ok2 := fieldByIndex(e2, k.index)
+			switch {
+			case !ok1 && !ok2:
+				continue
+			case !ok1:
+				return false
+			case !ok2:
+				return true
+			}
+
+			if c := k.cmp(f1, f2); c != 0 {
+				return (c < 0) != k.desc
+			}
+		}
+		return false
+	}
+	if stable {
+		sort.SliceStable(v.Interface(), less)
+	} else {
+		sort.Slice(v.Interface(), less)
+	}
+	return nil
+}
+
+// compileFields parses spec into the keys sorting elements of type t.
+func compileFields(t reflect.Type, spec string) ([]fieldKey, error) {
+	st := t
+	for st.Kind() == reflect.Pointer {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sorthelper: cannot sort by fields elements of type %v, want a struct", t)
+	}
+
+	var keys []fieldKey
+	for _, term := range strings.Split(spec, ",") {
+		path := strings.TrimSpace(term)
+		var desc bool
+		switch {
+		case strings.HasPrefix(path, "-"):
+			desc, path = true, path[1:]
+		case strings.HasPrefix(path, "+"):
+			path = path[1:]
+		}
+		if path == "" {
+			return nil, fmt.Errorf("sorthelper: empty field in sort spec %q", spec)
+		}
+
+		index, ft, err := resolveField(st, path)
+		if err != nil {
+			return nil, fmt.Errorf("sorthelper: invalid field %q in sort spec %q: %v", path, spec, err)
+		}
+		cmp := fieldCmp(ft)
+		if cmp == nil {
+			return nil, fmt.Errorf("sorthelper: invalid field %q in sort spec %q: unsupported type %v", path, spec, ft)
+		}
+		keys = append(keys, fieldKey{index: index, cmp: cmp, desc: desc})
+	}
+	return keys, nil
+}
+
+// resolveField resolves the dotted path in the struct type t,
+// returning the index sequence of the field and its type with pointers removed.
+func resolveField(t reflect.Type, path string) ([]int, reflect.Type, error) {
+	var index []int
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("cannot select field %q of non-struct type %v", name, t)
+		}
+
+		f, n := lookupField(t, name)
+		switch {
+		case n == 0:
+			return nil, nil, fmt.Errorf("%v has no field %q", t, name)
+		case n > 1:
+			return nil, nil, fmt.Errorf("ambiguous field %q: %v has %d fields %q at the same depth", name, t, n, name)
+		}
+		index = append(index, f.Index...)
+		t = f.Type
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return index, t, nil
+}
+
+// lookupField finds the exported field of the struct type t matching name,
+// looking into embedded structs when t has no such field itself.
+// As by the Go selector rules, it searches one depth of embedding at a time
+// and takes the shallowest match; the exported fields of unexported embedded
+// structs are promoted too. It returns the number of fields matching at that
+// depth, 0 if there is none and more than 1 if name is ambiguous.
+func lookupField(t reflect.Type, name string) (reflect.StructField, int) {
+	type scan struct {
+		t     reflect.Type
+		index []int
+	}
+	current := []scan{{t: t}}
+	visited := make(map[reflect.Type]bool)
+	for len(current) > 0 {
+		var next []scan
+		var match reflect.StructField
+		count := 0
+		for _, sc := range current {
+			if visited[sc.t] {
+				continue // Already searched at a shallower depth.
+			}
+			for i := 0; i < sc.t.NumField(); i++ {
+				f := sc.t.Field(i)
+				if f.Anonymous {
+					et := f.Type
+					if et.Kind() == reflect.Pointer {
+						et = et.Elem()
+					}
+					if et.Kind() == reflect.Struct {
+						next = append(next, scan{et, append(append([]int(nil), sc.index...), i)})
+					}
+				}
+				if !f.IsExported() || !fieldMatches(f, name) {
+					continue
+				}
+				if count++; count == 1 {
+					match = f
+					match.Index = append(append([]int(nil), sc.index...), i)
+				}
+			}
+		}
+		if count > 0 {
+			return match, count
+		}
+		for _, sc := range current {
+			visited[sc.t] = true
+		}
+		current = next
+	}
+	return reflect.StructField{}, 0
+}
+
+// fieldMatches reports whether the field f is named name by its sort tag,
+// or by its name ignoring case in the absence of a tag.
+func fieldMatches(f reflect.StructField, name string) bool {
+	tag, tagged := f.Tag.Lookup("sort")
+	switch {
+	case tag == "-":
+		return false
+	case tagged && tag != "":
+		return tag == name
+	}
+	return strings.EqualFold(f.Name, name)
+}
+
+// fieldByIndex returns the field of the struct v, or of the struct v points to,
+// selected by index, following pointers. It reports false if a nil pointer
+// is met on the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	v, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Value{}, false // A nil pointer to a struct.
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// fieldCmp returns the comparison function for values of type t,
+// or nil if t is not supported.
+func fieldCmp(t reflect.Type) func(v1, v2 reflect.Value) int {
+	if t == timeType {
+		return func(v1, v2 reflect.Value) int { return timeOf(v1).Compare(timeOf(v2)) }
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(v1, v2 reflect.Value) int {
+			b1, b2 := v1.Bool(), v2.Bool()
+			switch {
+			case !b1 && b2:
+				return -1
+			case b1 && !b2:
+				return +1
+			}
+			return 0
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v1, v2 reflect.Value) int { return compareOrdered(v1.Int(), v2.Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v1, v2 reflect.Value) int { return compareOrdered(v1.Uint(), v2.Uint()) }
+	case reflect.Float32, reflect.Float64:
+		return func(v1, v2 reflect.Value) int { return compareOrdered(v1.Float(), v2.Float()) }
+	case reflect.String:
+		return func(v1, v2 reflect.Value) int { return compareOrdered(v1.String(), v2.String()) }
+	}
+	return nil
+}
+
+// timeOf returns the time.Time held by v. A field promoted through an unexported
+// embedded struct cannot be read with Interface, but as an element of a slice,
+// or reached through a pointer, it is addressable.
+func timeOf(v reflect.Value) time.Time {
+	if v.CanInterface() {
+		return v.Interface().(time.Time)
+	}
+	return *(*time.Time)(unsafe.Pointer(v.UnsafeAddr()))
+}
@@ -0,0 +1,203 @@
+package sorthelper_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+type Author struct {
+	Name string
+}
+
+type Audit struct {
+	Created time.Time
+}
+
+type Post struct {
+	Audit
+	Title  string
+	Score  *float64 `sort:"rank"`
+	Views  uint
+	Secret int `sort:"-"`
+	Author *Author
+	hidden int
+}
+
+type audit struct {
+	Updated time.Time
+	Editor  string `sort:"by"`
+}
+
+type Draft struct {
+	audit
+	*Author
+	Title string
+}
+
+type Byline struct{ Author }
+
+type Credit struct {
+	Name string
+}
+
+// Shadowed has a field Name at depth 1, through Credit,
+// shadowing the field Name at depth 2, through Byline.
+type Shadowed struct {
+	Byline
+	Credit
+}
+
+// Ambiguous has two fields Name at depth 1.
+type Ambiguous struct {
+	Author
+	Credit
+}
+
+func score(f float64) *float64 { return &f }
+
+func postTitles(posts []*Post) string {
+	titles := make([]string, len(posts))
+	for i, p := range posts {
+		titles[i] = p.Title
+	}
+	return fmt.Sprint(titles)
+}
+
+func TestSortByFields(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	newPosts := func() []*Post {
+		return []*Post{
+			{Audit{t0.Add(3 * time.Hour)}, "c", score(2), 10, 0, &Author{"ann"}, 0},
+			{Audit{t0.Add(1 * time.Hour)}, "a", nil, 30, 0, nil, 0},
+			{Audit{t0.Add(2 * time.Hour)}, "d", score(1), 10, 0, &Author{"bob"}, 0},
+			{Audit{t0.Add(4 * time.Hour)}, "b", score(2), 20, 0, &Author{"ann"}, 0},
+		}
+	}
+
+	for _, tt := range []struct {
+		spec string
+		want string
+	}{
+		{"title", "[a b c d]"},
+		{"-Title", "[d c b a]"},
+		{"rank,title", "[d b c a]"},
+		{"-rank, title", "[b c d a]"},
+		{"views,-title", "[d c b a]"},
+		{"author.name,-views", "[b c d a]"},
+		{"created", "[a d c b]"},
+		{"Audit.Created", "[a d c b]"},
+		{"+views,created", "[d c b a]"},
+	} {
+		posts := newPosts()
+		if err := SortByFields(posts, tt.spec); err != nil {
+			t.Errorf("SortByFields(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := postTitles(posts); got != tt.want {
+			t.Errorf("SortByFields(%q) = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestSortByFieldsErrors(t *testing.T) {
+	t.Parallel()
+
+	posts := []Post{{Title: "b"}, {Title: "a"}}
+	for _, tt := range []struct {
+		s    any
+		spec string
+		err  string
+	}{
+		{posts, "", "empty field"},
+		{posts, "title,", "empty field"},
+		{posts, "nope", `has no field "nope"`},
+		{posts, "secret", `has no field "secret"`},
+		{posts, "hidden", `has no field "hidden"`},
+		{posts, "score", `has no field "score"`},
+		{posts, "author", "unsupported type"},
+		{posts, "title.x", "non-struct type string"},
+		{posts[0], "title", "want a slice"},
+		{[]int{1}, "x", "want a struct"},
+		{[]Ambiguous{}, "name", `ambiguous field "name"`},
+	} {
+		err := StableByFields(tt.s, tt.spec)
+		if err == nil || !bytes.Contains([]byte(err.Error()), []byte(tt.err)) {
+			t.Errorf("StableByFields(%T, %q) = %v, want error containing %q", tt.s, tt.spec, err, tt.err)
+		}
+	}
+	if got := posts[0].Title + posts[1].Title; got != "ba" {
+		t.Errorf("invalid spec modified the slice: %s", got)
+	}
+
+	if err := SortByFields(&posts, "title"); err != nil || posts[0].Title != "a" {
+		t.Errorf("SortByFields(&posts) = %v, posts = %v", err, posts)
+	}
+}
+
+func TestSortByFieldsPromoted(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	newDrafts := func() []Draft {
+		return []Draft{
+			{audit{t0.Add(2 * time.Hour), "bob"}, &Author{"ann"}, "b"},
+			{audit{t0.Add(3 * time.Hour), "ann"}, nil, "c"},
+			{audit{t0.Add(1 * time.Hour), "bob"}, &Author{"cid"}, "a"},
+		}
+	}
+
+	for _, tt := range []struct {
+		spec string
+		want string
+	}{
+		{"updated", "[a b c]"},
+		{"-by,-updated", "[b a c]"},
+		{"name", "[b a c]"},
+		{"-author.name", "[a b c]"},
+	} {
+		drafts := newDrafts()
+		if err := SortByFields(drafts, tt.spec); err != nil {
+			t.Errorf("SortByFields(%q): %v", tt.spec, err)
+			continue
+		}
+		titles := make([]string, len(drafts))
+		for i, d := range drafts {
+			titles[i] = d.Title
+		}
+		if got := fmt.Sprint(titles); got != tt.want {
+			t.Errorf("SortByFields(%q) = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+
+	if err := SortByFields([]Draft{}, "audit.updated"); err == nil {
+		t.Errorf("SortByFields(%q) selected an unexported field", "audit.updated")
+	}
+}
+
+func TestSortByFieldsShadowed(t *testing.T) {
+	t.Parallel()
+
+	s := []Shadowed{
+		{Byline{Author{"a1"}}, Credit{"b2"}},
+		{Byline{Author{"a2"}}, Credit{"b1"}},
+	}
+	if err := SortByFields(s, "name"); err != nil {
+		t.Fatalf("SortByFields: %v", err)
+	}
+	if s[0].Name != "b1" || s[1].Name != "b2" {
+		t.Errorf("SortByFields(%q) = %v, want sorted by Credit.Name", "name", s)
+	}
+
+	if err := SortByFields(s, "byline.name"); err != nil {
+		t.Fatalf("SortByFields: %v", err)
+	}
+	if s[0].Byline.Name != "a1" || s[1].Byline.Name != "a2" {
+		t.Errorf("SortByFields(%q) = %v, want sorted by Byline.Name", "byline.name", s)
+	}
+}
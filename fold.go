@@ -0,0 +1,132 @@
+// This file implements case-insensitive ordering of strings.
+
+package sorthelper
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FoldCompare compares the strings a and b under Unicode simple case folding,
+// as used by strings.EqualFold, so that "Go" and "GO" compare equal.
+// It returns -1, 0 or +1 as for strings.Compare, and doesn't allocate.
+//
+// Each rune is compared as the least rune of its case folding orbit,
+// which is the upper-case letter for ASCII letters. Bytes that are not valid UTF-8
+// are ordered after all runes, by their value.
+func FoldCompare(a, b string) int {
+	for a != "" && b != "" {
+		var ra, rb rune
+		if c := a[0]; c < utf8.RuneSelf {
+			ra, a = rune(foldASCII(c)), a[1:]
+		} else {
+			r, size := utf8.DecodeRuneInString(a)
+			ra, a = foldRune(r, c, size), a[size:]
+		}
+		if c := b[0]; c < utf8.RuneSelf {
+			rb, b = rune(foldASCII(c)), b[1:]
+		} else {
+			r, size := utf8.DecodeRuneInString(b)
+			rb, b = foldRune(r, c, size), b[size:]
+		}
+		if ra != rb {
+			if ra < rb {
+				return -1
+			}
+			return +1
+		}
+	}
+
+	switch {
+	case a != "":
+		return +1
+	case b != "":
+		return -1
+	}
+	return 0
+}
+
+// FoldLess reports whether a is less than b under Unicode simple case folding,
+// as defined by FoldCompare.
+func FoldLess(a, b string) bool { return FoldCompare(a, b) < 0 }
+
+// foldASCII returns the least rune of the case folding orbit of the ASCII byte c.
+func foldASCII(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	return c
+}
+
+// foldRune returns the least rune of the case folding orbit of r, decoded from
+// size bytes starting with c. Invalid encodings map to values above utf8.MaxRune.
+func foldRune(r rune, c byte, size int) rune {
+	if r == utf8.RuneError && size == 1 {
+		return utf8.MaxRune + 1 + rune(c)
+	}
+	least := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < least {
+			least = f
+		}
+	}
+	return least
+}
+
+// FoldStringSlice implements sort.Interface by providing Less and using the Len and
+// Swap methods of the embedded value, sorting in increasing order under Unicode
+// simple case folding, as defined by FoldCompare.
+type FoldStringSlice[E ~string] struct{ Slice[E] }
+
+func (x FoldStringSlice[E]) Less(i, j int) bool {
+	return FoldLess(string(x.Slice[i]), string(x.Slice[j]))
+}
+
+// Sort is a convenience method: x.Sort() calls sort.Sort(x).
+func (x FoldStringSlice[E]) Sort() { sort.Sort(x) }
+
+// Stable is a convenience method: x.Stable() calls sort.Stable(x).
+func (x FoldStringSlice[E]) Stable() { sort.Stable(x) }
+
+// Reverse is a convenience method: x.Reverse() calls sort.Sort(sort.Reverse(x)).
+func (x FoldStringSlice[E]) Reverse() { sort.Sort(sort.Reverse(x)) }
+
+// IsSorted is a convenience method: x.IsSorted() calls sort.IsSorted(x).
+func (x FoldStringSlice[E]) IsSorted() bool { return sort.IsSorted(x) }
+
+// Search returns the result of applying SearchFold to the receiver and x.
+func (x FoldStringSlice[E]) Search(e E) int { return SearchFold(x.Slice, e) }
+
+// FoldStrings sorts a slice of strings in increasing order ignoring case,
+// as defined by FoldCompare. The order of strings equal but for case is unspecified.
+func FoldStrings[E ~string](x []E) { FoldStringSlice[E]{x}.Sort() }
+
+// FoldStringsStable sorts a slice of strings in increasing order ignoring case,
+// as defined by FoldCompare, ordering strings equal but for case by their bytes,
+// as for Strings. The result doesn't depend on the original order of the strings.
+func FoldStringsStable[E ~string](x []E) {
+	sort.Sort(foldBytesSlice[E]{x})
+}
+
+// foldBytesSlice orders strings by FoldCompare, then by their bytes.
+type foldBytesSlice[E ~string] struct{ Slice[E] }
+
+func (x foldBytesSlice[E]) Less(i, j int) bool {
+	a, b := string(x.Slice[i]), string(x.Slice[j])
+	if c := FoldCompare(a, b); c != 0 {
+		return c < 0
+	}
+	return a < b
+}
+
+// FoldStringsAreSorted reports whether the slice x is sorted in increasing order
+// ignoring case.
+func FoldStringsAreSorted[E ~string](x []E) bool { return FoldStringSlice[E]{x}.IsSorted() }
+
+// SearchFold searches for x in a slice of strings sorted in increasing order ignoring case
+// and returns the index as specified by Search: the index of the first string equal
+// to x but for case, or the index to insert x if there is none (it could be len(a)).
+func SearchFold[E ~string](a []E, x E) int {
+	return sort.Search(len(a), func(i int) bool { return FoldCompare(string(a[i]), string(x)) >= 0 })
+}
@@ -0,0 +1,84 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestFoldCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "a", -1},
+		{"Go", "GO", 0},
+		{"go", "gopher", -1},
+		{"apple", "Banana", -1},
+		{"a_b", "aB", +1}, // Letters compare as upper case, before '_'.
+		{"straße", "STRASSE", +1},
+		{"ſ", "S", 0}, // LATIN SMALL LETTER LONG S folds to S.
+		{"K", "k", 0}, // KELVIN SIGN folds to K.
+		{"σ", "Σ", 0},
+		{"ς", "Σ", 0},
+		{"Ärger", "ärger", 0},
+		{"\xff", "\xfe", +1},
+		{"\xff", "ÿ", +1},
+	}
+	for _, tt := range tests {
+		if got := FoldCompare(tt.a, tt.b); got != tt.want {
+			t.Errorf("FoldCompare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := FoldCompare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("FoldCompare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+		if got := FoldLess(tt.a, tt.b); got != (tt.want < 0) {
+			t.Errorf("FoldLess(%q, %q) = %t", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestFoldCompareAllocs(t *testing.T) {
+	if n := testing.AllocsPerRun(100, func() { FoldCompare("Hello, Wörld", "hello, wÖrld") }); n != 0 {
+		t.Errorf("FoldCompare allocates %v times, want 0", n)
+	}
+}
+
+func TestFoldStrings(t *testing.T) {
+	t.Parallel()
+
+	want := []string{"Apple", "apple", "apricot", "BANANA", "Banana", "banana", "Cherry"}
+	r := rand.New(rand.NewSource(1))
+	for k := 0; k < 10; k++ {
+		data := append([]string(nil), want...)
+		r.Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+
+		FoldStrings(data)
+		if !FoldStringsAreSorted(data) {
+			t.Fatalf("FoldStringsAreSorted(%q) = false after FoldStrings", data)
+		}
+
+		FoldStringsStable(data)
+		for i := range data {
+			if data[i] != want[i] {
+				t.Fatalf("FoldStringsStable = %q, want %q", data, want)
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		x    string
+		want int
+	}{{"APPLE", 0}, {"apq", 2}, {"banana", 3}, {"bananas", 6}, {"zebra", 7}} {
+		if got := SearchFold(want, tt.x); got != tt.want {
+			t.Errorf("SearchFold(%q) = %d, want %d", tt.x, got, tt.want)
+		}
+		if got := (FoldStringSlice[string]{want}).Search(tt.x); got != tt.want {
+			t.Errorf("Search(%q) = %d, want %d", tt.x, got, tt.want)
+		}
+	}
+}
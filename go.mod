@@ -2,4 +2,7 @@ module github.com/weiwenchen2022/sorthelper
 
 go 1.20
 
-require golang.org/x/exp v0.0.0-20230321023759-10a507213a29
+require (
+	golang.org/x/exp v0.0.0-20230321023759-10a507213a29
+	golang.org/x/text v0.14.0
+)
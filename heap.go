@@ -0,0 +1,261 @@
+// This file implements typed binary heaps and priority queues.
+
+package sorthelper
+
+import "golang.org/x/exp/constraints"
+
+// Heap is a binary min-heap: Pop and Peek return the least element
+// as determined by its less function. It takes the place of a
+// heap.Interface implementation for the container/heap package.
+type Heap[E any] struct {
+	s    []E
+	less func(e1, e2 *E) bool
+}
+
+// NewHeap returns a Heap ordered by the operator <, holding the elements of s.
+// The heap takes ownership of s, which is rearranged in place.
+// The complexity is O(n) where n = len(s).
+func NewHeap[E constraints.Ordered](s []E) *Heap[E] {
+	return NewHeapFunc(s, orderedLess[E])
+}
+
+// NewHeapFunc returns a Heap ordered by the less function less, as used by Sorter,
+// holding the elements of s.
+// The heap takes ownership of s, which is rearranged in place.
+// The complexity is O(n) where n = len(s).
+func NewHeapFunc[E any](s []E, less func(e1, e2 *E) bool) *Heap[E] {
+	h := &Heap[E]{s: s, less: less}
+	for i := len(s)/2 - 1; i >= 0; i-- {
+		h.down(i, len(s))
+	}
+	return h
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[E]) Len() int { return len(h.s) }
+
+// Push pushes the element e onto the heap.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[E]) Push(e E) {
+	h.s = append(h.s, e)
+	h.up(len(h.s) - 1)
+}
+
+// Pop removes and returns the minimum element from the heap.
+// The complexity is O(log n) where n = h.Len().
+// Pop panics if the heap is empty.
+func (h *Heap[E]) Pop() E {
+	n := len(h.s) - 1
+	h.s[0], h.s[n] = h.s[n], h.s[0]
+	h.down(0, n)
+	return h.shrink()
+}
+
+// Peek returns the minimum element of the heap without removing it.
+// Peek panics if the heap is empty.
+func (h *Heap[E]) Peek() E { return h.s[0] }
+
+// Remove removes and returns the element at index i from the heap,
+// where indexes are those of the slice returned by Slice.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[E]) Remove(i int) E {
+	n := len(h.s) - 1
+	if n != i {
+		h.s[i], h.s[n] = h.s[n], h.s[i]
+		if !h.down(i, n) {
+			h.up(i)
+		}
+	}
+	return h.shrink()
+}
+
+// Fix re-establishes the heap ordering after the element at index i has changed its value,
+// where indexes are those of the slice returned by Slice.
+// The complexity is O(log n) where n = h.Len().
+func (h *Heap[E]) Fix(i int) {
+	if !h.down(i, len(h.s)) {
+		h.up(i)
+	}
+}
+
+// Slice returns the elements of the heap, in heap order.
+// An element may be modified in place as long as Fix is called after.
+func (h *Heap[E]) Slice() []E { return h.s }
+
+// shrink removes and returns the last element of the heap slice.
+func (h *Heap[E]) shrink() E {
+	n := len(h.s) - 1
+	e := h.s[n]
+	var zero E
+	h.s[n] = zero // Don't retain a reference.
+	h.s = h.s[:n]
+	return e
+}
+
+func (h *Heap[E]) up(j int) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !h.less(&h.s[j], &h.s[i]) {
+			break
+		}
+		h.s[i], h.s[j] = h.s[j], h.s[i]
+		j = i
+	}
+}
+
+func (h *Heap[E]) down(i0, n int) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 { // j1 < 0 after int overflow
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && h.less(&h.s[j2], &h.s[j1]) {
+			j = j2 // = 2*i + 2  // right child
+		}
+		if !h.less(&h.s[j], &h.s[i]) {
+			break
+		}
+		h.s[i], h.s[j] = h.s[j], h.s[i]
+		i = j
+	}
+	return i > i0
+}
+
+// An Item is a handle to an element of a PriorityQueue,
+// used to update or remove the element.
+type Item[E any] struct {
+	value E
+	index int // The index of the item in the queue, or -1 once removed.
+}
+
+// Value returns the element held by the item.
+func (it *Item[E]) Value() E { return it.value }
+
+// Queued reports whether the item is still in its PriorityQueue.
+func (it *Item[E]) Queued() bool { return it.index >= 0 }
+
+// PriorityQueue is an indexed priority queue: Pop and Peek return the least
+// element as determined by its less function, and every element pushed is
+// given an Item handle through which its priority can be changed later,
+// as in a decrease-key operation.
+type PriorityQueue[E any] struct {
+	items []*Item[E]
+	less  func(e1, e2 *E) bool
+}
+
+// NewPriorityQueue returns an empty PriorityQueue ordered by the operator <.
+func NewPriorityQueue[E constraints.Ordered]() *PriorityQueue[E] {
+	return NewPriorityQueueFunc(orderedLess[E])
+}
+
+// NewPriorityQueueFunc returns an empty PriorityQueue ordered by the less function less,
+// as used by Sorter.
+func NewPriorityQueueFunc[E any](less func(e1, e2 *E) bool) *PriorityQueue[E] {
+	return &PriorityQueue[E]{less: less}
+}
+
+// Len returns the number of elements in the queue.
+func (pq *PriorityQueue[E]) Len() int { return len(pq.items) }
+
+// Push adds the element e to the queue and returns its handle.
+// The complexity is O(log n) where n = pq.Len().
+func (pq *PriorityQueue[E]) Push(e E) *Item[E] {
+	it := &Item[E]{value: e, index: len(pq.items)}
+	pq.items = append(pq.items, it)
+	pq.up(it.index)
+	return it
+}
+
+// Pop removes and returns the minimum element from the queue.
+// The complexity is O(log n) where n = pq.Len().
+// Pop panics if the queue is empty.
+func (pq *PriorityQueue[E]) Pop() E {
+	return pq.Remove(pq.items[0])
+}
+
+// Peek returns the minimum element of the queue without removing it.
+// Peek panics if the queue is empty.
+func (pq *PriorityQueue[E]) Peek() E { return pq.items[0].value }
+
+// PeekItem returns the handle of the minimum element of the queue.
+// PeekItem panics if the queue is empty.
+func (pq *PriorityQueue[E]) PeekItem() *Item[E] { return pq.items[0] }
+
+// Update replaces the element of the item it with e and moves it to its new place in the queue,
+// whether its priority increased or decreased.
+// The complexity is O(log n) where n = pq.Len().
+// Update panics if it is not in the queue.
+func (pq *PriorityQueue[E]) Update(it *Item[E], e E) {
+	pq.check(it)
+	it.value = e
+	if !pq.down(it.index, len(pq.items)) {
+		pq.up(it.index)
+	}
+}
+
+// Remove removes the item it from the queue and returns its element.
+// The complexity is O(log n) where n = pq.Len().
+// Remove panics if it is not in the queue.
+func (pq *PriorityQueue[E]) Remove(it *Item[E]) E {
+	pq.check(it)
+	i, n := it.index, len(pq.items)-1
+	if i != n {
+		pq.swap(i, n)
+		if !pq.down(i, n) {
+			pq.up(i)
+		}
+	}
+	pq.items[n] = nil // Don't retain a reference.
+	pq.items = pq.items[:n]
+	it.index = -1
+	return it.value
+}
+
+func (pq *PriorityQueue[E]) check(it *Item[E]) {
+	if it.index < 0 || it.index >= len(pq.items) || pq.items[it.index] != it {
+		panic("sorthelper: item is not in the priority queue")
+	}
+}
+
+func (pq *PriorityQueue[E]) lessItem(i, j int) bool {
+	return pq.less(&pq.items[i].value, &pq.items[j].value)
+}
+
+func (pq *PriorityQueue[E]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *PriorityQueue[E]) up(j int) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !pq.lessItem(j, i) {
+			break
+		}
+		pq.swap(i, j)
+		j = i
+	}
+}
+
+func (pq *PriorityQueue[E]) down(i0, n int) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 { // j1 < 0 after int overflow
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && pq.lessItem(j2, j1) {
+			j = j2 // = 2*i + 2  // right child
+		}
+		if !pq.lessItem(j, i) {
+			break
+		}
+		pq.swap(i, j)
+		i = j
+	}
+	return i > i0
+}
@@ -0,0 +1,116 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func verifyHeap[E any](t *testing.T, h *Heap[E], less func(e1, e2 *E) bool) {
+	t.Helper()
+	s := h.Slice()
+	for i := 1; i < len(s); i++ {
+		if parent := (i - 1) / 2; less(&s[i], &s[parent]) {
+			t.Fatalf("heap invariant violated: [%d] = %v < [%d] = %v", i, s[i], parent, s[parent])
+		}
+	}
+}
+
+func TestHeap(t *testing.T) {
+	t.Parallel()
+
+	less := func(i1, i2 *int) bool { return *i1 < *i2 }
+	r := rand.New(rand.NewSource(1))
+	data := make([]int, 200)
+	for i := range data {
+		data[i] = r.Intn(100)
+	}
+	want := append([]int(nil), data...)
+	sort.Ints(want)
+
+	h := NewHeap(append([]int(nil), data...))
+	verifyHeap(t, h, less)
+	for i := 0; h.Len() > 0; i++ {
+		if got := h.Peek(); got != want[i] {
+			t.Fatalf("Peek = %d, want %d", got, want[i])
+		}
+		if got := h.Pop(); got != want[i] {
+			t.Fatalf("Pop = %d, want %d", got, want[i])
+		}
+		verifyHeap(t, h, less)
+	}
+
+	h = NewHeapFunc[int](nil, func(i1, i2 *int) bool { return *i1 > *i2 })
+	for _, x := range data {
+		h.Push(x)
+	}
+	h.Slice()[10] = -1
+	h.Fix(10)
+	h.Remove(20)
+	verifyHeap(t, h, func(i1, i2 *int) bool { return *i1 > *i2 })
+	if h.Len() != len(data)-1 {
+		t.Errorf("Len = %d, want %d", h.Len(), len(data)-1)
+	}
+	prev := h.Pop()
+	for h.Len() > 0 {
+		x := h.Pop()
+		if x > prev {
+			t.Fatalf("Pop = %d after %d from a max-heap", x, prev)
+		}
+		prev = x
+	}
+	if prev != -1 {
+		t.Errorf("last Pop = %d, want -1", prev)
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Parallel()
+
+	pq := NewPriorityQueue[int]()
+	r := rand.New(rand.NewSource(1))
+	items := make([]*Item[int], 100)
+	for i := range items {
+		items[i] = pq.Push(r.Intn(1000))
+	}
+
+	// Decrease every other key, increase the others, and remove a few.
+	for i, it := range items {
+		if i%2 == 0 {
+			pq.Update(it, it.Value()-1000)
+		} else {
+			pq.Update(it, it.Value()+1000)
+		}
+	}
+	for _, i := range []int{3, 50, 99} {
+		if got := pq.Remove(items[i]); got != items[i].Value() {
+			t.Errorf("Remove = %d, want %d", got, items[i].Value())
+		}
+		if items[i].Queued() {
+			t.Errorf("item %d still queued after Remove", i)
+		}
+	}
+
+	var got []int
+	for pq.Len() > 0 {
+		if pq.PeekItem().Value() != pq.Peek() {
+			t.Fatalf("PeekItem and Peek disagree")
+		}
+		got = append(got, pq.Pop())
+	}
+	if len(got) != len(items)-3 || !sort.IntsAreSorted(got) {
+		t.Errorf("Pop order = %v", got)
+	}
+	if got[0] >= 0 || got[len(got)-1] < 1000 {
+		t.Errorf("updated priorities not honored: %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Update of a removed item didn't panic")
+		}
+	}()
+	pq.Update(items[0], 0)
+}
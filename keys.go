@@ -0,0 +1,204 @@
+// This file implements sorting by cached keys.
+
+package sorthelper
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// keySorter sorts a slice of keys together with the permutation
+// that records the original position of each key.
+type keySorter[K constraints.Ordered] struct {
+	keys []K
+	perm []int
+}
+
+func (x *keySorter[K]) Len() int { return len(x.keys) }
+
+// Less orders keys by the operator <, with not-a-number (NaN) values
+// ordered before other values.
+func (x *keySorter[K]) Less(i, j int) bool { return isLess(x.keys[i], x.keys[j]) }
+
+func (x *keySorter[K]) Swap(i, j int) {
+	x.keys[i], x.keys[j] = x.keys[j], x.keys[i]
+	x.perm[i], x.perm[j] = x.perm[j], x.perm[i]
+}
+
+// isLess reports whether a is less than b, ordering not-a-number (NaN)
+// values before any others.
+func isLess[K constraints.Ordered](a, b K) bool {
+	return a < b || (a != a && b == b)
+}
+
+// compareOrdered returns -1, 0 or +1 depending on whether a is less than,
+// equal to or greater than b, with not-a-number (NaN) values
+// ordered before any others and equal to each other.
+func compareOrdered[K constraints.Ordered](a, b K) int {
+	switch {
+	case isLess(a, b):
+		return -1
+	case isLess(b, a):
+		return +1
+	}
+	return 0
+}
+
+// identity returns the identity permutation of length n.
+func identity(n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	return perm
+}
+
+// permute rearranges s in place so that the new s[i] is the old s[perm[i]],
+// following the cycles of perm. perm is only read, so that it can be shared.
+func permute[E any](s []E, perm []int) {
+	placed := newBitset(len(perm))
+	for i := range perm {
+		if placed.has(i) {
+			continue // Already placed by an earlier cycle.
+		}
+
+		tmp := s[i]
+		j := i
+		for {
+			k := perm[j]
+			placed.add(j)
+			if k == i {
+				s[j] = tmp
+				break
+			}
+			s[j] = s[k]
+			j = k
+		}
+	}
+}
+
+// A bitset is a set of small non-negative integers.
+type bitset []uint64
+
+// newBitset returns an empty bitset for the integers below n.
+func newBitset(n int) bitset { return make(bitset, (n+63)/64) }
+
+func (b bitset) has(i int) bool { return b[i/64]&(1<<(i%64)) != 0 }
+
+func (b bitset) add(i int) { b[i/64] |= 1 << (i % 64) }
+
+// sortByKey computes the key of each element of s once, sorts the keys
+// and then moves the elements of s into the order of their keys.
+func sortByKey[E any, K constraints.Ordered](s []E, key func(*E) K, stable bool) {
+	ks := &keySorter[K]{
+		keys: make([]K, len(s)),
+		perm: identity(len(s)),
+	}
+	for i := range s {
+		ks.keys[i] = key(&s[i])
+	}
+
+	if stable {
+		sort.Stable(ks)
+	} else {
+		sort.Sort(ks)
+	}
+	permute(s, ks.perm)
+}
+
+// SortByKey sorts the slice s in increasing order of the keys computed by key,
+// with not-a-number (NaN) keys ordered before other keys.
+// The key function is called exactly once for each element,
+// which makes SortByKey preferable to Sorter when keys are expensive to compute.
+//
+// The sort is not guaranteed to be stable. For a stable sort, use StableByKey.
+func SortByKey[E any, K constraints.Ordered](s []E, key func(*E) K) {
+	sortByKey(s, key, false)
+}
+
+// StableByKey sorts the slice s in increasing order of the keys computed by key,
+// while keeping the original order of elements with equal keys.
+// The key function is called exactly once for each element.
+func StableByKey[E any, K constraints.Ordered](s []E, key func(*E) K) {
+	sortByKey(s, key, true)
+}
+
+// A Key is a sort key for use with MultiSorter.OrderedByKeys, created by ByKey.
+type Key[E any] struct {
+	// cache computes the key of every element of s and returns
+	// a function comparing the keys of s[i] and s[j].
+	cache func(s []E) func(i, j int) int
+	desc  bool
+}
+
+// ByKey returns a Key that orders elements in increasing order of the keys computed by key,
+// with not-a-number (NaN) keys ordered before other keys.
+func ByKey[E any, K constraints.Ordered](key func(*E) K) Key[E] {
+	return Key[E]{
+		cache: func(s []E) func(i, j int) int {
+			keys := make([]K, len(s))
+			for i := range s {
+				keys[i] = key(&s[i])
+			}
+			return func(i, j int) int { return compareOrdered(keys[i], keys[j]) }
+		},
+	}
+}
+
+// Desc returns a copy of k that orders elements in decreasing order of their keys.
+func (k Key[E]) Desc() Key[E] {
+	k.desc = !k.desc
+	return k
+}
+
+// sortByKeys computes every key of every element of s once, sorts the
+// positions of the elements by their keys and then moves the elements
+// into that order.
+// If o is not nil, it counts the comparisons and swaps of the positions.
+func sortByKeys[E any](s []E, keys []Key[E], stable bool, o *Observer) {
+	cmps := make([]func(i, j int) int, len(keys))
+	for k, key := range keys {
+		cmps[k] = key.cache(s)
+	}
+
+	perm := identity(len(s))
+	data := o.wrap(&indexSorter{perm, func(p, q int) bool {
+		for k, cmp := range cmps {
+			if c := cmp(p, q); c != 0 {
+				return (c < 0) != keys[k].desc
+			}
+		}
+		return false
+	}})
+	if stable {
+		sort.Stable(data)
+	} else {
+		sort.Sort(data)
+	}
+	permute(s, perm)
+}
+
+// indexSorter sorts the positions perm of elements as determined by less.
+type indexSorter struct {
+	perm []int
+	less func(p, q int) bool
+}
+
+func (x *indexSorter) Len() int           { return len(x.perm) }
+func (x *indexSorter) Swap(i, j int)      { x.perm[i], x.perm[j] = x.perm[j], x.perm[i] }
+func (x *indexSorter) Less(i, j int) bool { return x.less(x.perm[i], x.perm[j]) }
+
+// OrderedByKeys sorts the slice within according to the keys, in order.
+// Each key is computed exactly once for each element.
+// The sort is not guaranteed to be stable. For a stable sort, use StableByKeys.
+func (ms *MultiSorter[E]) OrderedByKeys(keys ...Key[E]) {
+	ms.obs.observe("MultiSorter.OrderedByKeys", func() { sortByKeys(ms.s, keys, false, ms.obs) })
+}
+
+// StableByKeys sorts the slice within according to the keys, in order,
+// while keeping the original order of equal elements.
+// Each key is computed exactly once for each element.
+func (ms *MultiSorter[E]) StableByKeys(keys ...Key[E]) {
+	ms.obs.observe("MultiSorter.StableByKeys", func() { sortByKeys(ms.s, keys, true, ms.obs) })
+}
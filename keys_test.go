@@ -0,0 +1,67 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestSortByKey(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]pair, 1000)
+	for i := range data {
+		data[i] = pair{r.Intn(50), i}
+	}
+
+	calls := 0
+	StableByKey(data, func(p *pair) int {
+		calls++
+		return p.key
+	})
+	if calls != len(data) {
+		t.Errorf("key called %d times, want %d", calls, len(data))
+	}
+	if !sort.SliceIsSorted(data, func(i, j int) bool {
+		if data[i].key != data[j].key {
+			return data[i].key < data[j].key
+		}
+		return data[i].index < data[j].index
+	}) {
+		t.Errorf("StableByKey is not stable")
+	}
+
+	r.Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+	SortByKey(data, func(p *pair) int { return -p.index })
+	for i := range data {
+		if data[i].index != len(data)-1-i {
+			t.Fatalf("SortByKey: data[%d].index = %d, want %d", i, data[i].index, len(data)-1-i)
+		}
+	}
+}
+
+func TestOrderedByKeys(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]pair, 1000)
+	for i := range data {
+		data[i] = pair{r.Intn(10), r.Intn(100)}
+	}
+
+	NewMultiSorter(data).OrderedByKeys(
+		ByKey(func(p *pair) int { return p.key }),
+		ByKey(func(p *pair) int { return p.index }).Desc(),
+	)
+	if !sort.SliceIsSorted(data, func(i, j int) bool {
+		if data[i].key != data[j].key {
+			return data[i].key < data[j].key
+		}
+		return data[i].index > data[j].index
+	}) {
+		t.Errorf("OrderedByKeys didn't sort")
+	}
+}
@@ -0,0 +1,259 @@
+// This file implements merging of sorted slices and streams.
+
+package sorthelper
+
+import (
+	"io"
+
+	"golang.org/x/exp/constraints"
+)
+
+// A Merger merges sorted inputs into a single sorted output.
+// The zero Merger is not usable: Less must be set.
+type Merger[E any] struct {
+	// Less defines the order of the inputs, as for Sorter.
+	Less func(e1, e2 *E) bool
+
+	// Stable requests that equal elements are output in the order of
+	// the inputs they come from, and in their order within each input.
+	// Otherwise the order of equal elements is unspecified, which saves
+	// a call to Less for every comparison.
+	Stable bool
+
+	// Unique requests that only the first of each group of equal elements
+	// is output, dropping duplicates both within and across inputs.
+	Unique bool
+}
+
+// Slices returns a new slice holding the elements of the sorted slices s, merged.
+func (m Merger[E]) Slices(s ...[]E) []E {
+	n := 0
+	for _, x := range s {
+		n += len(x)
+	}
+	dst := make([]E, n)
+
+	switch len(s) {
+	case 0:
+		return dst
+	case 1:
+		copy(dst, s[0])
+	case 2:
+		// merge keeps the elements of its first input first, which is stable.
+		merge(dst, s[0], s[1], m.Less)
+	default:
+		srcs := make([]Iterator[E], len(s))
+		for i, x := range s {
+			srcs[i] = SliceIterator(x)
+		}
+		it := newMergeIterator(m.Less, srcs, m.Stable, m.Unique)
+		for i := range dst {
+			e, err := it.Next()
+			if err != nil {
+				return dst[:i]
+			}
+			dst[i] = e
+		}
+		return dst
+	}
+
+	if m.Unique {
+		dst = compact(dst, m.Less)
+	}
+	return dst
+}
+
+// Iterators returns an Iterator yielding the elements of the sorted iterators srcs, merged.
+// Elements are read from srcs as they are needed.
+// The first error returned by one of srcs, other than io.EOF, stops the merge
+// and is returned by every later call to Next.
+func (m Merger[E]) Iterators(srcs ...Iterator[E]) Iterator[E] {
+	return newMergeIterator(m.Less, srcs, m.Stable, m.Unique)
+}
+
+// Chans returns a channel receiving the elements of the sorted channels srcs, merged.
+// The returned channel is closed once all srcs are closed and drained.
+// The caller must receive all the elements from it, or the goroutine
+// doing the merge never exits.
+func (m Merger[E]) Chans(srcs ...<-chan E) <-chan E {
+	its := make([]Iterator[E], len(srcs))
+	for i, c := range srcs {
+		its[i] = chanIterator[E](c)
+	}
+	it := newMergeIterator(m.Less, its, m.Stable, m.Unique)
+
+	out := make(chan E)
+	go func() {
+		defer close(out)
+		for {
+			e, err := it.Next()
+			if err != nil {
+				return
+			}
+			out <- e
+		}
+	}()
+	return out
+}
+
+// chanIterator is an Iterator receiving elements from a channel.
+type chanIterator[E any] <-chan E
+
+func (c chanIterator[E]) Next() (E, error) {
+	e, ok := <-c
+	if !ok {
+		return e, io.EOF
+	}
+	return e, nil
+}
+
+// MergeSorted returns a new slice holding the elements of the slices s,
+// each sorted in increasing order, merged in increasing order.
+// Equal elements are output in the order of the slices they come from.
+func MergeSorted[E constraints.Ordered](s ...[]E) []E {
+	return Merger[E]{Less: orderedLess[E], Stable: true}.Slices(s...)
+}
+
+// MergeSortedFunc returns a new slice holding the elements of the slices s,
+// each sorted as determined by less, merged.
+// Equal elements are output in the order of the slices they come from.
+func MergeSortedFunc[E any](less func(e1, e2 *E) bool, s ...[]E) []E {
+	return Merger[E]{Less: less, Stable: true}.Slices(s...)
+}
+
+// compact removes the consecutive duplicates from the sorted slice s,
+// keeping the first of each group of equal elements.
+func compact[E any](s []E, less func(e1, e2 *E) bool) []E {
+	if len(s) < 2 {
+		return s
+	}
+	i := 1
+	for j := 1; j < len(s); j++ {
+		if less(&s[i-1], &s[j]) {
+			s[i] = s[j]
+			i++
+		}
+	}
+	return s[:i]
+}
+
+// mergeIterator is an Iterator merging sorted iterators.
+// It keeps the heads of the sources in a binary heap ordered by less,
+// breaking ties by source index when the merge is stable.
+type mergeIterator[E any] struct {
+	less    func(e1, e2 *E) bool
+	srcs    []Iterator[E]
+	heads   []mergeHead[E] // Heap of the next element of each active source.
+	stable  bool
+	unique  bool
+	started bool
+	err     error
+}
+
+type mergeHead[E any] struct {
+	e   E
+	src int
+}
+
+func newMergeIterator[E any](less func(e1, e2 *E) bool, srcs []Iterator[E], stable, unique bool) *mergeIterator[E] {
+	return &mergeIterator[E]{less: less, srcs: srcs, stable: stable, unique: unique}
+}
+
+func (it *mergeIterator[E]) headLess(i, j int) bool {
+	h1, h2 := &it.heads[i], &it.heads[j]
+	if it.less(&h1.e, &h2.e) {
+		return true
+	}
+	if !it.stable || it.less(&h2.e, &h1.e) {
+		return false
+	}
+	return h1.src < h2.src
+}
+
+func (it *mergeIterator[E]) up(j int) {
+	for j > 0 {
+		i := (j - 1) / 2 // parent
+		if !it.headLess(j, i) {
+			break
+		}
+		it.heads[i], it.heads[j] = it.heads[j], it.heads[i]
+		j = i
+	}
+}
+
+func (it *mergeIterator[E]) down(i int) {
+	n := len(it.heads)
+	for {
+		j := 2*i + 1
+		if j >= n {
+			break
+		}
+		if j2 := j + 1; j2 < n && it.headLess(j2, j) {
+			j = j2 // right child
+		}
+		if !it.headLess(j, i) {
+			break
+		}
+		it.heads[i], it.heads[j] = it.heads[j], it.heads[i]
+		i = j
+	}
+}
+
+// advance replaces the top of the heap with the next element of its source,
+// or removes it if the source is exhausted.
+func (it *mergeIterator[E]) advance() error {
+	top := &it.heads[0]
+	next, err := it.srcs[top.src].Next()
+	switch {
+	case err == io.EOF:
+		last := len(it.heads) - 1
+		it.heads[0] = it.heads[last]
+		it.heads = it.heads[:last]
+	case err != nil:
+		it.err = err
+		return err
+	default:
+		top.e = next
+	}
+	it.down(0)
+	return nil
+}
+
+func (it *mergeIterator[E]) Next() (E, error) {
+	var zero E
+	if !it.started {
+		it.started = true
+		for i, src := range it.srcs {
+			e, err := src.Next()
+			if err == io.EOF {
+				continue
+			}
+			if err != nil {
+				it.err = err
+				return zero, err
+			}
+			it.heads = append(it.heads, mergeHead[E]{e, i})
+			it.up(len(it.heads) - 1)
+		}
+	}
+	if it.err != nil {
+		return zero, it.err
+	}
+	if len(it.heads) == 0 {
+		return zero, io.EOF
+	}
+
+	e := it.heads[0].e
+	if err := it.advance(); err != nil {
+		return zero, err
+	}
+	if it.unique {
+		// The heads are not less than e, so those not greater are equal.
+		for len(it.heads) > 0 && !it.less(&e, &it.heads[0].e) {
+			if err := it.advance(); err != nil {
+				return zero, err
+			}
+		}
+	}
+	return e, nil
+}
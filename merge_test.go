@@ -0,0 +1,105 @@
+package sorthelper_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestMergeSorted(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, k := range []int{0, 1, 2, 3, 8} {
+		var inputs [][]int
+		var want []int
+		for i := 0; i < k; i++ {
+			s := make([]int, r.Intn(100))
+			for j := range s {
+				s[j] = r.Intn(50)
+			}
+			sort.Ints(s)
+			inputs = append(inputs, s)
+			want = append(want, s...)
+		}
+		sort.Ints(want)
+
+		got := MergeSorted(inputs...)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("k=%d: MergeSorted = %v, want %v", k, got, want)
+		}
+
+		unique := Merger[int]{Less: func(i1, i2 *int) bool { return *i1 < *i2 }, Unique: true}
+		got = unique.Slices(inputs...)
+		var wantUnique []int
+		for i, x := range want {
+			if i == 0 || x != want[i-1] {
+				wantUnique = append(wantUnique, x)
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(wantUnique) {
+			t.Errorf("k=%d: Unique Slices = %v, want %v", k, got, wantUnique)
+		}
+	}
+}
+
+func TestMergeSortedStable(t *testing.T) {
+	t.Parallel()
+
+	inputs := make([][]pair, 5)
+	for i := range inputs {
+		for key := 0; key < 10; key++ {
+			inputs[i] = append(inputs[i], pair{key, i})
+		}
+	}
+	got := MergeSortedFunc(func(p1, p2 *pair) bool { return p1.key < p2.key }, inputs...)
+	for i := 1; i < len(got); i++ {
+		if got[i-1].key == got[i].key && got[i-1].index > got[i].index {
+			t.Fatalf("MergeSortedFunc is not stable: %v", got)
+		}
+	}
+}
+
+func TestMergerStreams(t *testing.T) {
+	t.Parallel()
+
+	m := Merger[int]{Less: func(i1, i2 *int) bool { return *i1 < *i2 }, Stable: true}
+	it := m.Iterators(SliceIterator([]int{1, 4, 7}), SliceIterator([]int{2, 5}), SliceIterator([]int{3, 6, 9}))
+	var got []int
+	for {
+		x, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, x)
+	}
+	if want := "[1 2 3 4 5 6 7 9]"; fmt.Sprint(got) != want {
+		t.Errorf("Iterators = %v, want %v", got, want)
+	}
+
+	chans := make([]<-chan int, 3)
+	for i := range chans {
+		c := make(chan int)
+		go func(i int) {
+			defer close(c)
+			for x := i; x < 30; x += 3 {
+				c <- x
+			}
+		}(i)
+		chans[i] = c
+	}
+	got = got[:0]
+	for x := range m.Chans(chans...) {
+		got = append(got, x)
+	}
+	if len(got) != 30 || !sort.IntsAreSorted(got) {
+		t.Errorf("Chans = %v", got)
+	}
+}
@@ -0,0 +1,109 @@
+// This file implements natural ordering of strings.
+
+package sorthelper
+
+import (
+	"sort"
+	"strings"
+)
+
+// NaturalCompare compares the strings a and b in natural order, which orders
+// runs of ASCII digits by their numeric value, so that "file2" comes before "file10".
+// It returns -1, 0 or +1 as for strings.Compare.
+//
+// The numeric value of a digit run ignores its leading zeros, and runs of any length
+// are compared without converting them to integers. Apart from digit runs,
+// the strings are compared byte-wise, which orders UTF-8 encoded runes by code point.
+// Strings equal in natural order, such as "a01" and "a1", are ordered byte-wise,
+// so that only identical strings compare equal.
+func NaturalCompare(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if !isDigit(a[i]) || !isDigit(b[j]) {
+			if a[i] != b[j] {
+				if a[i] < b[j] {
+					return -1
+				}
+				return +1
+			}
+			i++
+			j++
+			continue
+		}
+
+		// Compare the digit runs starting at i and j, without their leading zeros.
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		ea, eb := i, j
+		for ea < len(a) && isDigit(a[ea]) {
+			ea++
+		}
+		for eb < len(b) && isDigit(b[eb]) {
+			eb++
+		}
+		// A longer run of significant digits is a greater number.
+		if n, m := ea-i, eb-j; n != m {
+			if n < m {
+				return -1
+			}
+			return +1
+		}
+		if c := strings.Compare(a[i:ea], b[j:eb]); c != 0 {
+			return c
+		}
+		i, j = ea, eb
+	}
+
+	switch {
+	case len(a)-i < len(b)-j:
+		return -1
+	case len(a)-i > len(b)-j:
+		return +1
+	}
+	return strings.Compare(a, b)
+}
+
+// NaturalLess reports whether a is less than b in natural order, as defined by NaturalCompare.
+func NaturalLess(a, b string) bool { return NaturalCompare(a, b) < 0 }
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// NaturalStringSlice implements sort.Interface by providing Less and using the Len and
+// Swap methods of the embedded value, sorting in natural order, as defined by NaturalCompare.
+type NaturalStringSlice[E ~string] struct{ Slice[E] }
+
+func (x NaturalStringSlice[E]) Less(i, j int) bool {
+	return NaturalLess(string(x.Slice[i]), string(x.Slice[j]))
+}
+
+// Sort is a convenience method: x.Sort() calls sort.Sort(x).
+func (x NaturalStringSlice[E]) Sort() { sort.Sort(x) }
+
+// Stable is a convenience method: x.Stable() calls sort.Stable(x).
+func (x NaturalStringSlice[E]) Stable() { sort.Stable(x) }
+
+// Reverse is a convenience method: x.Reverse() calls sort.Sort(sort.Reverse(x)).
+func (x NaturalStringSlice[E]) Reverse() { sort.Sort(sort.Reverse(x)) }
+
+// IsSorted is a convenience method: x.IsSorted() calls sort.IsSorted(x).
+func (x NaturalStringSlice[E]) IsSorted() bool { return sort.IsSorted(x) }
+
+// Search returns the result of applying SearchNaturalStrings to the receiver and x.
+func (x NaturalStringSlice[E]) Search(e E) int { return SearchNaturalStrings(x.Slice, e) }
+
+// NaturalStrings sorts a slice of strings in natural order, as defined by NaturalCompare.
+func NaturalStrings[E ~string](x []E) { NaturalStringSlice[E]{x}.Sort() }
+
+// NaturalStringsAreSorted reports whether the slice x is sorted in natural order.
+func NaturalStringsAreSorted[E ~string](x []E) bool { return NaturalStringSlice[E]{x}.IsSorted() }
+
+// SearchNaturalStrings searches for x in a slice of strings sorted in natural order
+// and returns the index as specified by Search. The return value is the index to insert x
+// if x is not present (it could be len(a)).
+func SearchNaturalStrings[E ~string](a []E, x E) int {
+	return sort.Search(len(a), func(i int) bool { return NaturalCompare(string(a[i]), string(x)) >= 0 })
+}
@@ -0,0 +1,75 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "a", -1},
+		{"file2", "file10", -1},
+		{"file10", "file10", 0},
+		{"file010", "file10", -1},
+		{"file10", "file010", +1},
+		{"file01b", "file1a", +1},
+		{"x0", "x00", -1},
+		{"x9", "x0010", -1},
+		{"a1", "ab", -1},
+		{"1a", "a", -1},
+		{"v1.10.0", "v1.9.3", +1},
+		{"99999999999999999999999999999", "100000000000000000000000000000", -1},
+		{"123456789012345678901234567891", "123456789012345678901234567890", +1},
+		{"été2", "été10", -1},
+		{"日本2", "日本10", -1},
+		{"zèbre", "zoo", +1},
+	}
+	for _, tt := range tests {
+		if got := NaturalCompare(tt.a, tt.b); got != tt.want {
+			t.Errorf("NaturalCompare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := NaturalCompare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("NaturalCompare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+		if got := NaturalLess(tt.a, tt.b); got != (tt.want < 0) {
+			t.Errorf("NaturalLess(%q, %q) = %t", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestNaturalStrings(t *testing.T) {
+	t.Parallel()
+
+	want := []string{"a", "a0", "a00", "a01", "a1", "a2", "a2b", "a010", "a10", "a011", "b", "b1c2", "b1c10"}
+	r := rand.New(rand.NewSource(1))
+	for k := 0; k < 10; k++ {
+		data := append([]string(nil), want...)
+		r.Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+		NaturalStrings(data)
+		if !NaturalStringsAreSorted(data) {
+			t.Fatalf("NaturalStringsAreSorted(%q) = false after NaturalStrings", data)
+		}
+		for i := range data {
+			if data[i] != want[i] {
+				t.Fatalf("NaturalStrings = %q, want %q", data, want)
+			}
+		}
+	}
+
+	for i, x := range want {
+		if got := SearchNaturalStrings(want, x); got != i {
+			t.Errorf("SearchNaturalStrings(%q) = %d, want %d", x, got, i)
+		}
+	}
+	if got := (NaturalStringSlice[string]{want}).Search("a3"); got != 7 {
+		t.Errorf("Search(a3) = %d, want 7", got)
+	}
+}
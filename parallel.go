@@ -0,0 +1,262 @@
+// This file implements parallel sorting.
+
+package sorthelper
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// parallelThreshold is the slice length below which the parallel sorts
+// fall back to their serial counterparts.
+const parallelThreshold = 1 << 13
+
+// mergeGrain is the length below which a merge is not split further
+// across goroutines.
+const mergeGrain = 1 << 11
+
+// parallelism returns the number of goroutines to use for procs:
+// if procs <= 0 it is runtime.GOMAXPROCS(0).
+func parallelism(procs int) int {
+	if procs <= 0 {
+		procs = runtime.GOMAXPROCS(0)
+	}
+	return procs
+}
+
+// parallelSort sorts s by splitting it into procs chunks, sorting each chunk
+// concurrently with sortChunk, and then merging the sorted chunks pairwise,
+// with each merge itself split across goroutines.
+// The merge prefers the left input on ties, so the result is stable
+// whenever sortChunk is stable.
+// It reports false, leaving s untouched, if s is too small to be worth
+// sorting in parallel; the caller must then sort s serially.
+func parallelSort[E any](s []E, procs int, sortChunk func([]E), less func(e1, e2 *E) bool) bool {
+	n := len(s)
+	procs = parallelism(procs)
+	if procs < 2 || n < parallelThreshold {
+		return false
+	}
+	if limit := n / (parallelThreshold / 2); procs > limit {
+		procs = limit
+	}
+
+	// Sort the chunks.
+	bounds := make([]int, procs+1)
+	for i := range bounds {
+		bounds[i] = i * n / procs
+	}
+	var wg sync.WaitGroup
+	wg.Add(procs)
+	for i := 0; i < procs; i++ {
+		go func(chunk []E) {
+			defer wg.Done()
+			sortChunk(chunk)
+		}(s[bounds[i]:bounds[i+1]])
+	}
+	wg.Wait()
+
+	// Merge adjacent chunks until a single one is left,
+	// ping-ponging between s and a scratch buffer.
+	src, dst := s, make([]E, n)
+	for len(bounds) > 2 {
+		pairs := (len(bounds) - 1) / 2
+		share := procs / pairs
+		wg.Add(pairs)
+		for i := 0; i+2 < len(bounds); i += 2 {
+			lo, mid, hi := bounds[i], bounds[i+1], bounds[i+2]
+			go func() {
+				defer wg.Done()
+				parallelMerge(dst[lo:hi], src[lo:mid], src[mid:hi], less, share)
+			}()
+		}
+		if len(bounds)%2 == 0 {
+			// Odd chunk out: carry it over unchanged.
+			lo := bounds[len(bounds)-2]
+			copy(dst[lo:], src[lo:])
+		}
+		wg.Wait()
+
+		next := bounds[:0]
+		for i := 0; i < len(bounds); i += 2 {
+			next = append(next, bounds[i])
+		}
+		if next[len(next)-1] != n {
+			next = append(next, n)
+		}
+		bounds = next
+		src, dst = dst, src
+	}
+
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+	return true
+}
+
+// parallelMerge merges the sorted slices a and b into dst, using up to procs goroutines.
+// Elements of a are placed before equal elements of b.
+func parallelMerge[E any](dst, a, b []E, less func(e1, e2 *E) bool, procs int) {
+	if procs < 2 || len(a)+len(b) < mergeGrain {
+		merge(dst, a, b, less)
+		return
+	}
+
+	// Split the longer input at its midpoint and the other one at the
+	// matching position, then merge both halves independently.
+	var i, j int
+	if len(a) >= len(b) {
+		i = len(a) / 2
+		j = sort.Search(len(b), func(k int) bool { return !less(&b[k], &a[i]) })
+	} else {
+		j = len(b) / 2
+		i = sort.Search(len(a), func(k int) bool { return less(&b[j], &a[k]) })
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		parallelMerge(dst[:i+j], a[:i], b[:j], less, procs/2)
+	}()
+	parallelMerge(dst[i+j:], a[i:], b[j:], less, procs-procs/2)
+	wg.Wait()
+}
+
+// merge merges the sorted slices a and b into dst, which must have room for both.
+// Elements of a are placed before equal elements of b.
+func merge[E any](dst, a, b []E, less func(e1, e2 *E) bool) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if less(&b[j], &a[i]) {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// orderedLess orders Ordered values by the operator <.
+func orderedLess[E constraints.Ordered](e1, e2 *E) bool { return *e1 < *e2 }
+
+// floatLess orders floats like Float64Slice.Less, with NaN values first.
+func floatLess[E constraints.Float](e1, e2 *E) bool {
+	return *e1 < *e2 || (isNaN(*e1) && !isNaN(*e2))
+}
+
+// ParallelSort sorts the slice x as determined by the operator <, in increasing order,
+// using up to procs goroutines. If procs <= 0, runtime.GOMAXPROCS(0) is used.
+// Small slices are sorted serially, as by SliceSort.
+//
+// The sort is not guaranteed to be stable. For a stable sort, use ParallelStable.
+func ParallelSort[E constraints.Ordered](x []E, procs int) {
+	if !parallelSort(x, procs, SliceSort[E], orderedLess[E]) {
+		SliceSort(x)
+	}
+}
+
+// ParallelStable sorts the slice x using the operator <, in ascending order,
+// keeping equal elements in their original order, using up to procs goroutines.
+// If procs <= 0, runtime.GOMAXPROCS(0) is used.
+// Small slices are sorted serially, as by SliceStable.
+func ParallelStable[E constraints.Ordered](x []E, procs int) {
+	if !parallelSort(x, procs, SliceStable[E], orderedLess[E]) {
+		SliceStable(x)
+	}
+}
+
+// ParallelInts sorts a slice of ints in increasing order using up to procs goroutines.
+// If procs <= 0, runtime.GOMAXPROCS(0) is used.
+func ParallelInts[E constraints.Integer](x []E, procs int) {
+	if !parallelSort(x, procs, Ints[E], orderedLess[E]) {
+		Ints(x)
+	}
+}
+
+// ParallelFloat64s sorts a slice of floats in increasing order using up to procs goroutines.
+// Not-a-number (NaN) values are ordered before other values.
+// If procs <= 0, runtime.GOMAXPROCS(0) is used.
+func ParallelFloat64s[E ~float64](x []E, procs int) {
+	if !parallelSort(x, procs, Float64s[E], floatLess[E]) {
+		Float64s(x)
+	}
+}
+
+// ParallelStrings sorts a slice of strings in increasing order using up to procs goroutines.
+// If procs <= 0, runtime.GOMAXPROCS(0) is used.
+func ParallelStrings[E ~string](x []E, procs int) {
+	if !parallelSort(x, procs, Strings[E], orderedLess[E]) {
+		Strings(x)
+	}
+}
+
+// ParallelOrderedBy sorts the slice within according to the by function,
+// using up to procs goroutines. If procs <= 0, runtime.GOMAXPROCS(0) is used.
+// The by function must be safe to call concurrently.
+// The sort is not guaranteed to be stable. For a stable sort, use ParallelStableBy.
+func (s *Sorter[E]) ParallelOrderedBy(procs int, by func(e1, e2 *E) bool) {
+	s.by = by
+	debugCheck(s.s, by)
+	parallelSortBy(s.obs, "Sorter.ParallelOrderedBy", s.s, procs, by,
+		func(chunk []E) sort.Interface { return &Sorter[E]{s: chunk, by: by} })
+}
+
+// ParallelStableBy sorts the slice within according to the by function,
+// while keeping the original order of equal elements,
+// using up to procs goroutines. If procs <= 0, runtime.GOMAXPROCS(0) is used.
+// The by function must be safe to call concurrently.
+func (s *Sorter[E]) ParallelStableBy(procs int, by func(e1, e2 *E) bool) {
+	s.by = by
+	debugCheck(s.s, by)
+	parallelSortBy(s.obs, "Sorter.ParallelStableBy", s.s, procs, by, nil)
+}
+
+// ParallelOrderedBy sorts the slice within according to the less functions, in order,
+// using up to procs goroutines. If procs <= 0, runtime.GOMAXPROCS(0) is used.
+// The less functions must be safe to call concurrently.
+// The sort is not guaranteed to be stable. For a stable sort, use ParallelStableBy.
+func (ms *MultiSorter[E]) ParallelOrderedBy(procs int, less ...func(e1, e2 *E) bool) {
+	ms.less, ms.cmp = less, nil
+	debugCheck(ms.s, ms.lessElem)
+	parallelSortBy(ms.obs, "MultiSorter.ParallelOrderedBy", ms.s, procs, ms.lessElem,
+		func(chunk []E) sort.Interface { return &MultiSorter[E]{s: chunk, less: less} })
+}
+
+// ParallelStableBy sorts the slice within in ascending order as determined by the less functions, in order,
+// while keeping the original order of equal elements,
+// using up to procs goroutines. If procs <= 0, runtime.GOMAXPROCS(0) is used.
+// The less functions must be safe to call concurrently.
+func (ms *MultiSorter[E]) ParallelStableBy(procs int, less ...func(e1, e2 *E) bool) {
+	ms.less, ms.cmp = less, nil
+	debugCheck(ms.s, ms.lessElem)
+	parallelSortBy(ms.obs, "MultiSorter.ParallelStableBy", ms.s, procs, ms.lessElem, nil)
+}
+
+// parallelSortBy sorts s as determined by less with parallelSort, or serially
+// if s is too small. The chunks are sorted with sort.Sort on the sort.Interface
+// returned by data, or with stableSort if data is nil.
+// If o is not nil, the sort is measured as a phase named name of o,
+// counting the comparisons and swaps of every goroutine.
+func parallelSortBy[E any](o *Observer, name string, s []E, procs int,
+	less func(e1, e2 *E) bool, data func(chunk []E) sort.Interface) {
+	o.observe(name, func() {
+		if o != nil {
+			less = observeLessConcurrent(o, less)
+		}
+		sortChunk := func(chunk []E) { stableSort(chunk, less, -1) }
+		if data != nil {
+			sortChunk = func(chunk []E) { sort.Sort(o.wrapConcurrent(data(chunk))) }
+		}
+		if !parallelSort(s, procs, sortChunk, less) {
+			sortChunk(s)
+		}
+	})
+}
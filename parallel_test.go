@@ -0,0 +1,115 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+type pair struct {
+	key, index int
+}
+
+func TestParallelInts(t *testing.T) {
+	t.Parallel()
+
+	n := 100000
+	if testing.Short() {
+		n /= 10
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, procs := range []int{0, 1, 2, 3, 4, 7} {
+		data := make([]int, n)
+		for i := range data {
+			data[i] = r.Int() - r.Int()
+		}
+		ParallelInts(data, procs)
+		if !IntsAreSorted(data) {
+			t.Errorf("procs=%d: ParallelInts didn't sort %d ints", procs, n)
+		}
+	}
+}
+
+func TestParallelStrings(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]string, 20000)
+	for i := range data {
+		b := make([]byte, 1+r.Intn(8))
+		r.Read(b)
+		data[i] = string(b)
+	}
+	ParallelStrings(data, 4)
+	if !StringsAreSorted(data) {
+		t.Errorf("ParallelStrings didn't sort %d strings", len(data))
+	}
+}
+
+func TestParallelStable(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, procs := range []int{2, 3, 5, 8} {
+		data := make([]pair, 50000)
+		for i := range data {
+			data[i] = pair{r.Intn(100), i}
+		}
+		NewSorter(data).ParallelStableBy(procs, func(p1, p2 *pair) bool { return p1.key < p2.key })
+		if !sort.SliceIsSorted(data, func(i, j int) bool {
+			if data[i].key != data[j].key {
+				return data[i].key < data[j].key
+			}
+			return data[i].index < data[j].index
+		}) {
+			t.Errorf("procs=%d: ParallelStableBy is not stable", procs)
+		}
+	}
+}
+
+func TestParallelMultiSorter(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]pair, 30000)
+	for i := range data {
+		data[i] = pair{r.Intn(10), r.Intn(1000)}
+	}
+	key := func(p1, p2 *pair) bool { return p1.key < p2.key }
+	index := func(p1, p2 *pair) bool { return p1.index > p2.index }
+	NewMultiSorter(data).ParallelOrderedBy(4, key, index)
+	if !sort.SliceIsSorted(data, func(i, j int) bool {
+		if data[i].key != data[j].key {
+			return data[i].key < data[j].key
+		}
+		return data[i].index > data[j].index
+	}) {
+		t.Errorf("ParallelOrderedBy didn't sort")
+	}
+}
+
+func BenchmarkParallelInts1M(b *testing.B) {
+	for _, bench := range [...]bench[int]{
+		{"Ints", Ints[int]},
+		{"ParallelInts", func(data []int) { ParallelInts(data, 0) }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.StopTimer()
+			r := rand.New(rand.NewSource(1))
+			unsorted := make([]int, 1<<20)
+			for i := range unsorted {
+				unsorted[i] = r.Int()
+			}
+			data := make([]int, len(unsorted))
+
+			for i := 0; i < b.N; i++ {
+				copy(data, unsorted)
+				b.StartTimer()
+				bench.f(data)
+				b.StopTimer()
+			}
+		})
+	}
+}
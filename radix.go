@@ -0,0 +1,98 @@
+// This file implements the radix sort engine used for integer slices.
+
+package sorthelper
+
+import (
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// radixThreshold is the slice length from which integer sorts switch
+// from comparison sorting to radix sorting. Below it the histogram and
+// scratch buffer overhead outweighs the gain.
+const radixThreshold = 1 << 8
+
+// radixSort sorts x in increasing order using a least significant digit
+// radix sort on bytes. It handles every width of signed and unsigned
+// integers by flipping the sign bit of signed values, so that the
+// unsigned order of the keys matches the signed order of the values.
+// Keys are taken relative to the smallest one, so only the digits needed
+// to cover the range of the values are sorted, and digits whose bytes are
+// the same for every element are skipped.
+// The sort is stable and uses a scratch buffer of len(x) elements.
+func radixSort[E constraints.Integer](x []E) {
+	n := len(x)
+	if n < 2 {
+		return
+	}
+
+	bits := uint(unsafe.Sizeof(x[0]) * 8)
+	mask := ^uint64(0) >> (64 - bits)
+	var flip uint64
+	if ^E(0) < 0 {
+		// Signed type: toggle the sign bit.
+		flip = 1 << (bits - 1)
+	}
+
+	// Find the range of the keys, noticing already sorted input on the way.
+	lo := (uint64(x[0]) & mask) ^ flip
+	hi, sorted := lo, true
+	for i := 1; i < n; i++ {
+		k := (uint64(x[i]) & mask) ^ flip
+		if k < lo {
+			lo = k
+		} else if k > hi {
+			hi = k
+		}
+		if sorted && x[i] < x[i-1] {
+			sorted = false
+		}
+	}
+	if sorted {
+		return
+	}
+	key := func(e E) uint64 { return ((uint64(e) & mask) ^ flip) - lo }
+
+	digits := 0
+	for r := hi - lo; r != 0; r >>= 8 {
+		digits++
+	}
+
+	// Build the histograms for all digits in a single pass.
+	var counts [8][256]int
+	for _, e := range x {
+		k := key(e)
+		for d := 0; d < digits; d++ {
+			counts[d][byte(k>>(8*d))]++
+		}
+	}
+
+	src, dst := x, make([]E, n)
+	for d := 0; d < digits; d++ {
+		c := &counts[d]
+		shift := uint(8 * d)
+		if c[byte(key(src[0])>>shift)] == n {
+			// Every element has the same byte here; nothing to do.
+			continue
+		}
+
+		// Turn counts into starting offsets.
+		offset := 0
+		for i, cnt := range c {
+			c[i] = offset
+			offset += cnt
+		}
+
+		for _, e := range src {
+			b := byte(key(e) >> shift)
+			dst[c[b]] = e
+			c[b]++
+		}
+		src, dst = dst, src
+	}
+
+	if &src[0] != &x[0] {
+		copy(x, src)
+	}
+}
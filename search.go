@@ -11,9 +11,10 @@ import (
 // Search searches for x in a sorted slice of a and returns the index.
 // The return value is the index to insert x if x is
 // not present (it could be len(a)).
-// The slice must be sorted in ascending order.
+// The slice must be sorted in ascending order, with not-a-number (NaN) values
+// before other values, as by Float64s.
 func Search[E constraints.Ordered](a []E, x E) int {
-	return sort.Search(len(a), func(i int) bool { return a[i] >= x })
+	return sort.Search(len(a), func(i int) bool { return !isLess(a[i], x) })
 }
 
 // Convenience wrappers for common cases.
@@ -29,9 +30,20 @@ func SearchInts[E constraints.Integer](a []E, x E) int {
 // SearchFloat64s searches for x in a sorted slice of float64s and returns the index
 // as specified by Search. The return value is the index to insert x if x is not
 // present (it could be len(a)).
-// The slice must be sorted in ascending order.
+// The slice must be sorted in ascending order, with not-a-number (NaN) values
+// before other values, as by Float64s; searching for NaN returns 0.
 func SearchFloat64s[E ~float64](a []E, x E) int {
-	return sort.Search(len(a), func(i int) bool { return a[i] >= x })
+	nan := isNaN(x)
+	return sort.Search(len(a), func(i int) bool { return a[i] >= x || nan })
+}
+
+// SearchFloat32s searches for x in a sorted slice of float32s and returns the index
+// as specified by SearchFloat64s.
+// The slice must be sorted in ascending order, with not-a-number (NaN) values
+// before other values, as by Float32s.
+func SearchFloat32s[E ~float32](a []E, x E) int {
+	nan := isNaN(x)
+	return sort.Search(len(a), func(i int) bool { return a[i] >= x || nan })
 }
 
 // SearchStrings searches for x in a sorted slice of strings and returns the index
@@ -48,5 +60,150 @@ func (p IntSlice[E]) Search(x E) int { return SearchInts(p.Slice, x) }
 // Search returns the result of applying SearchFloat64s to the receiver and x.
 func (p Float64Slice[E]) Search(x E) int { return SearchFloat64s(p.Slice, x) }
 
+// Search returns the result of applying SearchFloat32s to the receiver and x.
+func (p Float32Slice[E]) Search(x E) int { return SearchFloat32s(p.Slice, x) }
+
 // Search returns the result of applying SearchStrings to the receiver and x.
 func (p StringSlice[E]) Search(x E) int { return SearchStrings(p.Slice, x) }
+
+// LowerBound returns the index of the first element of the sorted slice a
+// that is not less than x, or len(a) if there is none. It is the same as Search.
+// The slice must be sorted in ascending order.
+func LowerBound[E constraints.Ordered](a []E, x E) int {
+	return Search(a, x)
+}
+
+// UpperBound returns the index of the first element of the sorted slice a
+// that is greater than x, or len(a) if there is none.
+// The slice must be sorted in ascending order, with not-a-number (NaN) values
+// before other values, as by Float64s.
+func UpperBound[E constraints.Ordered](a []E, x E) int {
+	return sort.Search(len(a), func(i int) bool { return isLess(x, a[i]) })
+}
+
+// Find searches for x in a sorted slice of a and returns the index
+// as specified by Search, and reports whether x is present at that index.
+// The slice must be sorted in ascending order, with not-a-number (NaN) values
+// before other values, as by Float64s; NaN values are equal to each other.
+func Find[E constraints.Ordered](a []E, x E) (int, bool) {
+	i := Search(a, x)
+	return i, i < len(a) && !isLess(x, a[i])
+}
+
+// EqualRange returns the range [lo, hi) of the elements of the sorted slice a
+// equal to x. If x is not present, lo == hi is the index to insert x.
+// The slice must be sorted in ascending order, with not-a-number (NaN) values
+// before other values, as by Float64s; NaN values are equal to each other.
+func EqualRange[E constraints.Ordered](a []E, x E) (lo, hi int) {
+	lo = Search(a, x)
+	hi = lo + UpperBound(a[lo:], x)
+	return lo, hi
+}
+
+// Count returns the number of elements of the sorted slice a equal to x.
+// The slice must be sorted in ascending order, with not-a-number (NaN) values
+// before other values, as by Float64s; NaN values are equal to each other.
+func Count[E constraints.Ordered](a []E, x E) int {
+	lo, hi := EqualRange(a, x)
+	return hi - lo
+}
+
+// Comparator-based variants.
+
+// SearchFunc searches for x in a slice a sorted as determined by less,
+// as used by Sorter, and returns the index of the first element that is
+// not less than x. The return value is the index to insert x if x is
+// not present (it could be len(a)).
+func SearchFunc[E any](a []E, x E, less func(e1, e2 *E) bool) int {
+	return sort.Search(len(a), func(i int) bool { return !less(&a[i], &x) })
+}
+
+// UpperBoundFunc returns the index of the first element of the slice a,
+// sorted as determined by less, that x is less than, or len(a) if there is none.
+func UpperBoundFunc[E any](a []E, x E, less func(e1, e2 *E) bool) int {
+	return sort.Search(len(a), func(i int) bool { return less(&x, &a[i]) })
+}
+
+// FindFunc searches for x in a slice a sorted as determined by less and returns
+// the index as specified by SearchFunc, and reports whether an element equal
+// to x, that is neither less nor greater than x, is present at that index.
+func FindFunc[E any](a []E, x E, less func(e1, e2 *E) bool) (int, bool) {
+	i := SearchFunc(a, x, less)
+	return i, i < len(a) && !less(&x, &a[i])
+}
+
+// EqualRangeFunc returns the range [lo, hi) of the elements of the slice a,
+// sorted as determined by less, equal to x.
+// If x is not present, lo == hi is the index to insert x.
+func EqualRangeFunc[E any](a []E, x E, less func(e1, e2 *E) bool) (lo, hi int) {
+	lo = SearchFunc(a, x, less)
+	hi = lo + UpperBoundFunc(a[lo:], x, less)
+	return lo, hi
+}
+
+// CountFunc returns the number of elements of the slice a,
+// sorted as determined by less, equal to x.
+func CountFunc[E any](a []E, x E, less func(e1, e2 *E) bool) int {
+	lo, hi := EqualRangeFunc(a, x, less)
+	return hi - lo
+}
+
+// Find returns the result of applying Find to the receiver and x.
+func (p IntSlice[E]) Find(x E) (int, bool) { return Find(p.Slice, x) }
+
+// UpperBound returns the result of applying UpperBound to the receiver and x.
+func (p IntSlice[E]) UpperBound(x E) int { return UpperBound(p.Slice, x) }
+
+// EqualRange returns the result of applying EqualRange to the receiver and x.
+func (p IntSlice[E]) EqualRange(x E) (lo, hi int) { return EqualRange(p.Slice, x) }
+
+// Count returns the result of applying Count to the receiver and x.
+func (p IntSlice[E]) Count(x E) int { return Count(p.Slice, x) }
+
+// Find searches for x in the receiver as Find does,
+// with not-a-number (NaN) values ordered before and equal to each other.
+func (p Float64Slice[E]) Find(x E) (int, bool) { return FindFunc(p.Slice, x, floatLess[E]) }
+
+// UpperBound returns the index of the first element of the receiver greater than x,
+// with not-a-number (NaN) values ordered before other values.
+func (p Float64Slice[E]) UpperBound(x E) int { return UpperBoundFunc(p.Slice, x, floatLess[E]) }
+
+// EqualRange returns the range of the elements of the receiver equal to x,
+// with not-a-number (NaN) values ordered before and equal to each other.
+func (p Float64Slice[E]) EqualRange(x E) (lo, hi int) {
+	return EqualRangeFunc(p.Slice, x, floatLess[E])
+}
+
+// Count returns the number of elements of the receiver equal to x,
+// with not-a-number (NaN) values equal to each other.
+func (p Float64Slice[E]) Count(x E) int { return CountFunc(p.Slice, x, floatLess[E]) }
+
+// Find searches for x in the receiver as Find does,
+// with not-a-number (NaN) values ordered before and equal to each other.
+func (p Float32Slice[E]) Find(x E) (int, bool) { return FindFunc(p.Slice, x, floatLess[E]) }
+
+// UpperBound returns the index of the first element of the receiver greater than x,
+// with not-a-number (NaN) values ordered before other values.
+func (p Float32Slice[E]) UpperBound(x E) int { return UpperBoundFunc(p.Slice, x, floatLess[E]) }
+
+// EqualRange returns the range of the elements of the receiver equal to x,
+// with not-a-number (NaN) values ordered before and equal to each other.
+func (p Float32Slice[E]) EqualRange(x E) (lo, hi int) {
+	return EqualRangeFunc(p.Slice, x, floatLess[E])
+}
+
+// Count returns the number of elements of the receiver equal to x,
+// with not-a-number (NaN) values equal to each other.
+func (p Float32Slice[E]) Count(x E) int { return CountFunc(p.Slice, x, floatLess[E]) }
+
+// Find returns the result of applying Find to the receiver and x.
+func (p StringSlice[E]) Find(x E) (int, bool) { return Find(p.Slice, x) }
+
+// UpperBound returns the result of applying UpperBound to the receiver and x.
+func (p StringSlice[E]) UpperBound(x E) int { return UpperBound(p.Slice, x) }
+
+// EqualRange returns the result of applying EqualRange to the receiver and x.
+func (p StringSlice[E]) EqualRange(x E) (lo, hi int) { return EqualRange(p.Slice, x) }
+
+// Count returns the result of applying Count to the receiver and x.
+func (p StringSlice[E]) Count(x E) int { return Count(p.Slice, x) }
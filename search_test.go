@@ -0,0 +1,132 @@
+package sorthelper_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestSearchFamily(t *testing.T) {
+	t.Parallel()
+
+	less := func(i1, i2 *int) bool { return *i1 < *i2 }
+	for _, a := range [][]int{
+		nil,
+		{1},
+		{1, 1, 1},
+		{1, 2, 2, 2, 3, 5, 5, 8},
+		{0, 0, 1, 1, 2, 2, 3, 3},
+	} {
+		for x := -1; x <= 9; x++ {
+			// Compute the expected results by linear scan.
+			lo, hi := 0, 0
+			for lo < len(a) && a[lo] < x {
+				lo++
+			}
+			for hi = lo; hi < len(a) && a[hi] == x; hi++ {
+			}
+
+			if got := LowerBound(a, x); got != lo {
+				t.Errorf("LowerBound(%v, %d) = %d, want %d", a, x, got, lo)
+			}
+			if got := UpperBound(a, x); got != hi {
+				t.Errorf("UpperBound(%v, %d) = %d, want %d", a, x, got, hi)
+			}
+			if i, found := Find(a, x); i != lo || found != (hi > lo) {
+				t.Errorf("Find(%v, %d) = %d, %t, want %d, %t", a, x, i, found, lo, hi > lo)
+			}
+			if l, h := EqualRange(a, x); l != lo || h != hi {
+				t.Errorf("EqualRange(%v, %d) = %d, %d, want %d, %d", a, x, l, h, lo, hi)
+			}
+			if got := Count(a, x); got != hi-lo {
+				t.Errorf("Count(%v, %d) = %d, want %d", a, x, got, hi-lo)
+			}
+
+			if got := SearchFunc(a, x, less); got != lo {
+				t.Errorf("SearchFunc(%v, %d) = %d, want %d", a, x, got, lo)
+			}
+			if got := UpperBoundFunc(a, x, less); got != hi {
+				t.Errorf("UpperBoundFunc(%v, %d) = %d, want %d", a, x, got, hi)
+			}
+			if i, found := FindFunc(a, x, less); i != lo || found != (hi > lo) {
+				t.Errorf("FindFunc(%v, %d) = %d, %t, want %d, %t", a, x, i, found, lo, hi > lo)
+			}
+			if l, h := EqualRangeFunc(a, x, less); l != lo || h != hi {
+				t.Errorf("EqualRangeFunc(%v, %d) = %d, %d, want %d, %d", a, x, l, h, lo, hi)
+			}
+			if got := CountFunc(a, x, less); got != hi-lo {
+				t.Errorf("CountFunc(%v, %d) = %d, want %d", a, x, got, hi-lo)
+			}
+
+			p := IntSlice[int]{a}
+			if l, h := p.EqualRange(x); l != lo || h != hi || p.Count(x) != hi-lo || p.UpperBound(x) != hi {
+				t.Errorf("IntSlice(%v) methods disagree for %d", a, x)
+			}
+			if i, found := p.Find(x); i != lo || found != (hi > lo) {
+				t.Errorf("IntSlice(%v).Find(%d) = %d, %t, want %d, %t", a, x, i, found, lo, hi > lo)
+			}
+		}
+	}
+}
+
+func TestSearchSliceTypes(t *testing.T) {
+	t.Parallel()
+
+	s := StringSlice[string]{[]string{"a", "b", "b", "c"}}
+	if lo, hi := s.EqualRange("b"); lo != 1 || hi != 3 {
+		t.Errorf("StringSlice.EqualRange = %d, %d, want 1, 3", lo, hi)
+	}
+	if i, found := s.Find("bb"); i != 3 || found {
+		t.Errorf("StringSlice.Find = %d, %t, want 3, false", i, found)
+	}
+
+	f := Float64Slice[float64]{[]float64{0.5, 1, 1, 1, 2.5}}
+	if n := f.Count(1); n != 3 {
+		t.Errorf("Float64Slice.Count = %d, want 3", n)
+	}
+	if i := f.UpperBound(1); i != 4 {
+		t.Errorf("Float64Slice.UpperBound = %d, want 4", i)
+	}
+}
+
+func TestSearchNaN(t *testing.T) {
+	t.Parallel()
+
+	nan := math.NaN()
+	a := []float64{2, nan, 1, nan, 1, 3}
+	Float64s(a) // [NaN NaN 1 1 2 3]
+
+	for _, tt := range []struct {
+		x      float64
+		lo, hi int
+	}{
+		{nan, 0, 2},
+		{math.Inf(-1), 2, 2},
+		{1, 2, 4},
+		{2.5, 5, 5},
+		{3, 5, 6},
+	} {
+		if got := Search(a, tt.x); got != tt.lo {
+			t.Errorf("Search(%v, %v) = %d, want %d", a, tt.x, got, tt.lo)
+		}
+		if got := SearchFloat64s(a, tt.x); got != tt.lo {
+			t.Errorf("SearchFloat64s(%v, %v) = %d, want %d", a, tt.x, got, tt.lo)
+		}
+		if got := UpperBound(a, tt.x); got != tt.hi {
+			t.Errorf("UpperBound(%v, %v) = %d, want %d", a, tt.x, got, tt.hi)
+		}
+		if i, found := Find(a, tt.x); i != tt.lo || found != (tt.hi > tt.lo) {
+			t.Errorf("Find(%v, %v) = %d, %t, want %d, %t", a, tt.x, i, found, tt.lo, tt.hi > tt.lo)
+		}
+		if lo, hi := EqualRange(a, tt.x); lo != tt.lo || hi != tt.hi {
+			t.Errorf("EqualRange(%v, %v) = %d, %d, want %d, %d", a, tt.x, lo, hi, tt.lo, tt.hi)
+		}
+		if got := Count(a, tt.x); got != tt.hi-tt.lo {
+			t.Errorf("Count(%v, %v) = %d, want %d", a, tt.x, got, tt.hi-tt.lo)
+		}
+		if lo, hi := (Float64Slice[float64]{a}).EqualRange(tt.x); lo != tt.lo || hi != tt.hi {
+			t.Errorf("Float64Slice.EqualRange(%v) = %d, %d, want %d, %d", tt.x, lo, hi, tt.lo, tt.hi)
+		}
+	}
+}
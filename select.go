@@ -0,0 +1,179 @@
+// This file implements selection and partial sorting.
+
+package sorthelper
+
+import (
+	"io"
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// NthElement rearranges the slice s so that s[n] is the element that would be there
+// if s were sorted in increasing order, with no element of s[:n] greater than s[n]
+// and no element of s[n+1:] less than it. The order within s[:n] and s[n+1:]
+// is unspecified. It runs in O(len(s)) expected time.
+// NthElement panics if n is out of range.
+func NthElement[E constraints.Ordered](s []E, n int) {
+	nthElement(s, n, orderedLess[E])
+}
+
+// NthElementFunc is like NthElement but orders the elements by the less function less,
+// as used by Sorter.
+func NthElementFunc[E any](s []E, n int, less func(e1, e2 *E) bool) {
+	nthElement(s, n, less)
+}
+
+// PartialSort rearranges the slice s so that s[:k] holds its k smallest elements
+// in increasing order. The order of the remaining elements is unspecified.
+// If k > len(s), the whole slice is sorted.
+func PartialSort[E constraints.Ordered](s []E, k int) {
+	partialSort(s, k, orderedLess[E])
+}
+
+// PartialSortFunc is like PartialSort but orders the elements by the less function less,
+// as used by Sorter.
+func PartialSortFunc[E any](s []E, k int, less func(e1, e2 *E) bool) {
+	partialSort(s, k, less)
+}
+
+// TopK returns a new slice holding the k smallest elements of s in increasing order,
+// leaving s unchanged. If k > len(s), all the elements of s are returned.
+// It runs in O(len(s) log k) time using O(k) extra memory.
+func TopK[E constraints.Ordered](s []E, k int) []E {
+	return TopKFunc(s, k, orderedLess[E])
+}
+
+// TopKFunc is like TopK but orders the elements by the less function less,
+// as used by Sorter.
+func TopKFunc[E any](s []E, k int, less func(e1, e2 *E) bool) []E {
+	top, _ := TopKIterator(SliceIterator(s), k, less)
+	return top
+}
+
+// TopKIterator returns the k smallest elements yielded by it, as determined by less,
+// in increasing order. It consumes it until io.EOF, holding no more than k elements
+// in memory at once, and returns the first other error along with the elements
+// seen so far.
+func TopKIterator[E any](it Iterator[E], k int, less func(e1, e2 *E) bool) ([]E, error) {
+	if k <= 0 {
+		return []E{}, nil
+	}
+
+	// Keep the k smallest elements in a max-heap, whose root is the one to evict.
+	greater := func(e1, e2 *E) bool { return less(e2, e1) }
+	h := NewHeapFunc(make([]E, 0, k), greater)
+	var err error
+	for {
+		var e E
+		e, err = it.Next()
+		if err != nil {
+			break
+		}
+		switch {
+		case h.Len() < k:
+			h.Push(e)
+		case less(&e, &h.Slice()[0]):
+			h.Slice()[0] = e
+			h.Fix(0)
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+
+	top := make([]E, h.Len())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i] = h.Pop()
+	}
+	return top, err
+}
+
+func partialSort[E any](s []E, k int, less func(e1, e2 *E) bool) {
+	if k <= 0 {
+		return
+	}
+	if k < len(s) {
+		nthElement(s, k-1, less)
+		s = s[:k-1] // s[k-1] is already in place.
+	}
+	NewSorter(s).OrderedBy(less)
+}
+
+// nthElement implements NthElement with an introspective quickselect:
+// when partitioning makes too little progress, it sorts the remaining
+// range instead, bounding the worst case to O(n log n).
+func nthElement[E any](s []E, n int, less func(e1, e2 *E) bool) {
+	if n < 0 || n >= len(s) {
+		panic("sorthelper: NthElement index out of range")
+	}
+
+	lo, hi := 0, len(s)
+	limit := 2 * bits.Len(uint(len(s)))
+	for hi-lo > 12 {
+		if limit == 0 {
+			NewSorter(s[lo:hi]).OrderedBy(less)
+			return
+		}
+		limit--
+
+		p := partition(s, lo, hi, less)
+		switch {
+		case n == p:
+			return
+		case n < p:
+			hi = p
+		default:
+			lo = p + 1
+		}
+	}
+	insertionSort(s[lo:hi], less)
+}
+
+// partition partitions s[lo:hi] around a median-of-three pivot and returns
+// the final index p of the pivot: no element of s[lo:p] is greater than s[p]
+// and no element of s[p+1:hi] is less than it.
+func partition[E any](s []E, lo, hi int, less func(e1, e2 *E) bool) int {
+	// Move the median of the first, middle and last elements to s[lo].
+	m, l := lo+(hi-lo)/2, hi-1
+	if less(&s[m], &s[lo]) {
+		s[m], s[lo] = s[lo], s[m]
+	}
+	if less(&s[l], &s[m]) {
+		s[l], s[m] = s[m], s[l]
+		if less(&s[m], &s[lo]) {
+			s[m], s[lo] = s[lo], s[m]
+		}
+	}
+	s[lo], s[m] = s[m], s[lo]
+
+	// Hoare partition; stopping on elements equal to the pivot
+	// keeps the parts balanced when there are many of them.
+	p := &s[lo]
+	i, j := lo+1, hi-1
+	for {
+		for i <= j && less(&s[i], p) {
+			i++
+		}
+		for i <= j && less(p, &s[j]) {
+			j--
+		}
+		if i >= j {
+			break
+		}
+		s[i], s[j] = s[j], s[i]
+		i++
+		j--
+	}
+	s[lo], s[j] = s[j], s[lo]
+	return j
+}
+
+// insertionSort sorts s using insertion sort.
+func insertionSort[E any](s []E, less func(e1, e2 *E) bool) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && less(&s[j], &s[j-1]); j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
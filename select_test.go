@@ -0,0 +1,127 @@
+package sorthelper_test
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestNthElement(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 10, 13, 100, 1000} {
+		for _, mod := range []int{2, 10, 1 << 30} {
+			data := make([]int, n)
+			for i := range data {
+				data[i] = r.Intn(mod)
+			}
+			sorted := append([]int(nil), data...)
+			sort.Ints(sorted)
+
+			for _, k := range []int{0, n / 3, n / 2, n - 1} {
+				s := append([]int(nil), data...)
+				NthElement(s, k)
+				if s[k] != sorted[k] {
+					t.Fatalf("n=%d k=%d: s[k] = %d, want %d", n, k, s[k], sorted[k])
+				}
+				for i := range s {
+					if (i < k && s[i] > s[k]) || (i > k && s[i] < s[k]) {
+						t.Fatalf("n=%d k=%d: s[%d] = %d is on the wrong side of %d", n, k, i, s[i], s[k])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPartialSort(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = r.Intn(500)
+	}
+	sorted := append([]int(nil), data...)
+	sort.Ints(sorted)
+
+	for _, k := range []int{0, 1, 10, 100, 999, 1000, 2000} {
+		s := append([]int(nil), data...)
+		PartialSort(s, k)
+		if k > len(s) {
+			k = len(s)
+		}
+		for i := 0; i < k; i++ {
+			if s[i] != sorted[i] {
+				t.Fatalf("k=%d: s[%d] = %d, want %d", k, i, s[i], sorted[i])
+			}
+		}
+
+		before := append([]int(nil), data...)
+		top := TopK(data, k)
+		if len(top) != k {
+			t.Fatalf("k=%d: len(TopK) = %d", k, len(top))
+		}
+		for i := range top {
+			if top[i] != sorted[i] {
+				t.Fatalf("k=%d: TopK[%d] = %d, want %d", k, i, top[i], sorted[i])
+			}
+		}
+		for i := range data {
+			if data[i] != before[i] {
+				t.Fatalf("TopK modified its input")
+			}
+		}
+	}
+
+	desc := func(i1, i2 *int) bool { return *i1 > *i2 }
+	s := append([]int(nil), data...)
+	PartialSortFunc(s, 5, desc)
+	for i := 0; i < 5; i++ {
+		if s[i] != sorted[len(sorted)-1-i] {
+			t.Fatalf("PartialSortFunc: s[%d] = %d, want %d", i, s[i], sorted[len(sorted)-1-i])
+		}
+	}
+}
+
+func TestTopKIteratorError(t *testing.T) {
+	t.Parallel()
+
+	errRead := errors.New("read failed")
+	top, err := TopKIterator[int64](&failingIterator{10, errRead}, 3, func(i1, i2 *int64) bool { return *i1 < *i2 })
+	if err != errRead {
+		t.Errorf("err = %v, want %v", err, errRead)
+	}
+	if len(top) != 3 || top[0] != 0 || top[2] != 2 {
+		t.Errorf("top = %v, want [0 1 2]", top)
+	}
+}
+
+func BenchmarkPartialSort64K(b *testing.B) {
+	for _, bench := range [...]bench[int]{
+		{"SliceSort", SliceSort[int]},
+		{"PartialSort100", func(data []int) { PartialSort(data, 100) }},
+		{"TopK100", func(data []int) { TopK(data, 100) }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.StopTimer()
+			r := rand.New(rand.NewSource(1))
+			unsorted := make([]int, 1<<16)
+			for i := range unsorted {
+				unsorted[i] = r.Int()
+			}
+			data := make([]int, len(unsorted))
+
+			for i := 0; i < b.N; i++ {
+				copy(data, unsorted)
+				b.StartTimer()
+				bench.f(data)
+				b.StopTimer()
+			}
+		})
+	}
+}
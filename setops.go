@@ -0,0 +1,204 @@
+// This file implements set operations on sorted slices.
+
+package sorthelper
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SetOps performs set operations on slices sorted as determined by Less.
+// The operations run in linear time, and switch to galloping search when
+// one input is much longer than the other, which takes O(m log(n/m)) comparisons
+// for inputs of lengths m <= n.
+//
+// The results are appended to a dst slice, which is returned extended, so that
+// a buffer can be reused across calls. The elements of dst must not overlap those
+// of the inputs, except that Intersect and Difference may write into a[:0].
+// The zero SetOps is not usable: Less must be set.
+type SetOps[E any] struct {
+	// Less defines the order of the inputs, as for Sorter.
+	Less func(e1, e2 *E) bool
+
+	// Multiset requests that equal elements are counted, as for multisets:
+	// an element occurring m times in a and n times in b occurs max(m, n) times
+	// in the union, min(m, n) times in the intersection, m-n times in the
+	// difference and |m-n| times in the symmetric difference.
+	// Otherwise the inputs are treated as sets and each element of the
+	// result occurs once, whatever the duplicates in the inputs.
+	Multiset bool
+}
+
+// gallopRatio is the ratio of the lengths of the inputs of a set operation
+// above which the longer input is skipped through with galloping search.
+const gallopRatio = 8
+
+// setOp selects which elements of a set operation are output.
+type setOp int
+
+const (
+	opUnion setOp = iota
+	opIntersect
+	opDifference
+	opSymmetricDifference
+)
+
+// Union appends to dst the elements in a or b, in order, and returns the extended slice.
+// Equal elements are taken from a first.
+func (o SetOps[E]) Union(dst, a, b []E) []E {
+	return o.apply(dst, a, b, opUnion)
+}
+
+// Intersect appends to dst the elements in both a and b, in order, and returns the extended slice.
+// Equal elements are taken from a.
+func (o SetOps[E]) Intersect(dst, a, b []E) []E {
+	return o.apply(dst, a, b, opIntersect)
+}
+
+// Difference appends to dst the elements in a but not in b, in order, and returns the extended slice.
+func (o SetOps[E]) Difference(dst, a, b []E) []E {
+	return o.apply(dst, a, b, opDifference)
+}
+
+// SymmetricDifference appends to dst the elements in either a or b but not both, in order,
+// and returns the extended slice.
+func (o SetOps[E]) SymmetricDifference(dst, a, b []E) []E {
+	return o.apply(dst, a, b, opSymmetricDifference)
+}
+
+func (o SetOps[E]) apply(dst, a, b []E, op setOp) []E {
+	keepA := op != opIntersect
+	keepB := op == opUnion || op == opSymmetricDifference
+	gallop := len(a) > gallopRatio*len(b) || len(b) > gallopRatio*len(a)
+
+	for len(a) > 0 && len(b) > 0 {
+		// Output or skip the elements of either input less than the head of the other.
+		if i := o.lowerBound(a, &b[0], gallop); i > 0 {
+			if keepA {
+				dst = o.appendRun(dst, a[:i])
+			}
+			a = a[i:]
+			continue
+		}
+		if j := o.lowerBound(b, &a[0], gallop); j > 0 {
+			if keepB {
+				dst = o.appendRun(dst, b[:j])
+			}
+			b = b[j:]
+			continue
+		}
+
+		// The heads are equal: count their occurrences in each input.
+		m, n := o.upperBound(a, &a[0]), o.upperBound(b, &b[0])
+		if o.Multiset {
+			switch op {
+			case opUnion:
+				dst = append(dst, a[:m]...)
+				if n > m {
+					dst = append(dst, b[m:n]...)
+				}
+			case opIntersect:
+				if n > m {
+					n = m
+				}
+				dst = append(dst, a[:n]...)
+			case opDifference, opSymmetricDifference:
+				if m > n {
+					dst = append(dst, a[n:m]...)
+				} else if op == opSymmetricDifference {
+					dst = append(dst, b[m:n]...)
+				}
+			}
+		} else if op == opUnion || op == opIntersect {
+			dst = append(dst, a[0])
+		}
+		a, b = a[m:], b[n:]
+	}
+
+	if keepA {
+		dst = o.appendRun(dst, a)
+	}
+	if keepB {
+		dst = o.appendRun(dst, b)
+	}
+	return dst
+}
+
+// appendRun appends run to dst, dropping duplicates unless o.Multiset is set.
+func (o SetOps[E]) appendRun(dst, run []E) []E {
+	n := len(dst)
+	dst = append(dst, run...)
+	if o.Multiset {
+		return dst
+	}
+	return dst[:n+len(compact(dst[n:], o.Less))]
+}
+
+// lowerBound returns the number of elements of s less than x.
+func (o SetOps[E]) lowerBound(s []E, x *E, gallop bool) int {
+	f := func(i int) bool { return !o.Less(&s[i], x) }
+	if gallop {
+		return gallopSearch(len(s), f)
+	}
+	i := 0
+	for i < len(s) && !f(i) {
+		i++
+	}
+	return i
+}
+
+// upperBound returns the number of elements of s not greater than x.
+func (o SetOps[E]) upperBound(s []E, x *E) int {
+	return gallopSearch(len(s), func(i int) bool { return o.Less(x, &s[i]) })
+}
+
+// gallopSearch is like sort.Search, but it first probes indexes 0, 1, 3, 7, ...
+// so that it takes O(log i) calls to f to find the result i, however large n is.
+func gallopSearch(n int, f func(int) bool) int {
+	lo, hi := 0, 0 // f(i) is false for i < lo.
+	for hi < n && !f(hi) {
+		lo, hi = hi+1, 2*hi+1
+	}
+	if hi > n {
+		hi = n
+	}
+	return lo + sort.Search(hi-lo, func(i int) bool { return f(lo + i) })
+}
+
+// Union returns a new slice holding the elements in a or b, which are sorted
+// in increasing order, in increasing order and without duplicates.
+func Union[E constraints.Ordered](a, b []E) []E {
+	return SetOps[E]{Less: orderedLess[E]}.Union(nil, a, b)
+}
+
+// Intersect returns a new slice holding the elements in both a and b, which are sorted
+// in increasing order, in increasing order and without duplicates.
+func Intersect[E constraints.Ordered](a, b []E) []E {
+	return SetOps[E]{Less: orderedLess[E]}.Intersect(nil, a, b)
+}
+
+// Difference returns a new slice holding the elements in a but not in b, which are sorted
+// in increasing order, in increasing order and without duplicates.
+func Difference[E constraints.Ordered](a, b []E) []E {
+	return SetOps[E]{Less: orderedLess[E]}.Difference(nil, a, b)
+}
+
+// SymmetricDifference returns a new slice holding the elements in either a or b but not both,
+// which are sorted in increasing order, in increasing order and without duplicates.
+func SymmetricDifference[E constraints.Ordered](a, b []E) []E {
+	return SetOps[E]{Less: orderedLess[E]}.SymmetricDifference(nil, a, b)
+}
+
+// Unique removes the consecutive duplicates from the slice s, sorted in increasing order,
+// keeping the first of each group of equal elements, and returns the shortened slice.
+// It works in place and runs in linear time.
+func Unique[E constraints.Ordered](s []E) []E {
+	return compact(s, orderedLess[E])
+}
+
+// UniqueFunc is like Unique but for a slice sorted as determined by less,
+// as used by Sorter.
+func UniqueFunc[E any](s []E, less func(e1, e2 *E) bool) []E {
+	return compact(s, less)
+}
@@ -0,0 +1,171 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+// setOpModel computes a set operation on sorted slices of small ints by counting.
+func setOpModel(a, b []int, multiset bool, count func(m, n int) int) []int {
+	ca, cb := map[int]int{}, map[int]int{}
+	for _, x := range a {
+		ca[x]++
+	}
+	for _, x := range b {
+		cb[x]++
+	}
+	var out []int
+	for x := 0; x < 100; x++ {
+		m, n := ca[x], cb[x]
+		if !multiset {
+			m, n = min1(m), min1(n)
+		}
+		for i := count(m, n); i > 0; i-- {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func min1(m int) int {
+	if m > 1 {
+		return 1
+	}
+	return m
+}
+
+func randomSorted(r *rand.Rand, n, max int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = r.Intn(max)
+	}
+	sort.Ints(s)
+	return s
+}
+
+func TestSetOps(t *testing.T) {
+	t.Parallel()
+
+	type op struct {
+		name  string
+		f     func(o SetOps[int], dst, a, b []int) []int
+		count func(m, n int) int
+	}
+	ops := []op{
+		{"Union", SetOps[int].Union, func(m, n int) int {
+			if m > n {
+				return m
+			}
+			return n
+		}},
+		{"Intersect", SetOps[int].Intersect, func(m, n int) int {
+			if m < n {
+				return m
+			}
+			return n
+		}},
+		{"Difference", SetOps[int].Difference, func(m, n int) int {
+			if m > n {
+				return m - n
+			}
+			return 0
+		}},
+		{"SymmetricDifference", SetOps[int].SymmetricDifference, func(m, n int) int {
+			if m > n {
+				return m - n
+			}
+			return n - m
+		}},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	less := func(i1, i2 *int) bool { return *i1 < *i2 }
+	for _, lens := range [][2]int{{0, 0}, {0, 10}, {10, 0}, {20, 20}, {100, 30}, {3, 200}, {500, 2}} {
+		a := randomSorted(r, lens[0], 100)
+		b := randomSorted(r, lens[1], 100)
+		for _, multiset := range []bool{false, true} {
+			o := SetOps[int]{Less: less, Multiset: multiset}
+			for _, op := range ops {
+				want := setOpModel(a, b, multiset, op.count)
+				got := op.f(o, []int{-1}, a, b)
+				if got[0] != -1 || !equalInts(got[1:], want) {
+					t.Errorf("%s(%d, %d elements), multiset %t = %v, want [-1] + %v",
+						op.name, len(a), len(b), multiset, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSetOpsInPlace(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(2))
+	a := randomSorted(r, 300, 100)
+	b := randomSorted(r, 100, 100)
+	for _, multiset := range []bool{false, true} {
+		o := SetOps[int]{Less: func(i1, i2 *int) bool { return *i1 < *i2 }, Multiset: multiset}
+
+		want := o.Intersect(nil, a, b)
+		if got := o.Intersect(append([]int(nil), a...)[:0], a, b); !equalInts(got, want) {
+			t.Errorf("Intersect into a[:0], multiset %t = %v, want %v", multiset, got, want)
+		}
+		want = o.Difference(nil, a, b)
+		x := append([]int(nil), a...)
+		if got := o.Difference(x[:0], x, b); !equalInts(got, want) {
+			t.Errorf("Difference into a[:0], multiset %t = %v, want %v", multiset, got, want)
+		}
+	}
+}
+
+func TestSetFunctions(t *testing.T) {
+	t.Parallel()
+
+	a := []string{"a", "b", "b", "d"}
+	b := []string{"b", "c", "d", "d", "e"}
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"Union", Union(a, b), []string{"a", "b", "c", "d", "e"}},
+		{"Intersect", Intersect(a, b), []string{"b", "d"}},
+		{"Difference", Difference(a, b), []string{"a"}},
+		{"SymmetricDifference", SymmetricDifference(a, b), []string{"a", "c", "e"}},
+		{"Unique", Unique(append([]string(nil), b...)), []string{"b", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		if len(tt.got) != len(tt.want) {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+			continue
+		}
+		for i := range tt.got {
+			if tt.got[i] != tt.want[i] {
+				t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+				break
+			}
+		}
+	}
+
+	type pair struct{ k, v int }
+	s := []pair{{1, 0}, {1, 1}, {2, 2}, {3, 3}, {3, 4}}
+	s = UniqueFunc(s, func(p1, p2 *pair) bool { return p1.k < p2.k })
+	if len(s) != 3 || s[0].v != 0 || s[1].v != 2 || s[2].v != 3 {
+		t.Errorf("UniqueFunc = %v, want the first of each key", s)
+	}
+}
+
+func BenchmarkIntersectUnbalanced(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	big := randomSorted(r, 1<<20, 1<<30)
+	small := randomSorted(r, 64, 1<<30)
+	o := SetOps[int]{Less: func(i1, i2 *int) bool { return *i1 < *i2 }}
+	dst := make([]int, 0, len(small))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = o.Intersect(dst[:0], small, big)
+	}
+}
@@ -11,14 +11,18 @@ import (
 // The sort is not guaranteed to be stable: equal elements
 // may be reversed from their original order.
 // For a stable sort, use StableSort.
+// Presorted runs are merged in O(n).
 func SliceSort[E constraints.Ordered](x []E) {
+	if sortAdaptiveOrdered(x) {
+		return
+	}
 	sort.Slice(x, func(i, j int) bool { return x[i] < x[j] })
 }
 
 // SliceStable sorts the slice x using the operator <, in ascending order,
 // keeping equal elements in their original order.
 func SliceStable[E constraints.Ordered](x []E) {
-	sort.SliceStable(x, func(i, j int) bool { return x[i] < x[j] })
+	stableSort(x, orderedLess[E], -1)
 }
 
 // SliceIsSorted reports whether the slice s is sorted in increasing order according to the operator <.
@@ -0,0 +1,438 @@
+// This file implements sorted containers.
+
+package sorthelper
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// sortedLoad is the typical length of the chunks of a sortedList.
+// Chunks are split when they grow to twice that length.
+const sortedLoad = 512
+
+// sortedList is a list of distinct elements kept sorted by cmp.
+// The elements are stored in a sequence of sorted chunks, so that an insertion
+// or deletion only moves the elements of one chunk, while the elements
+// stay contiguous enough for binary search and fast iteration.
+type sortedList[E any] struct {
+	cmp    func(e1, e2 *E) int
+	chunks [][]E
+	n      int
+}
+
+// search returns the position of the first element not less than x:
+// chunk index c and index i within the chunk, and reports whether it equals x.
+// If all elements are less than x, the position is the end of the last chunk.
+func (l *sortedList[E]) search(x *E) (c, i int, found bool) {
+	if len(l.chunks) == 0 {
+		return 0, 0, false
+	}
+	c = sort.Search(len(l.chunks), func(c int) bool {
+		chunk := l.chunks[c]
+		return l.cmp(&chunk[len(chunk)-1], x) >= 0
+	})
+	if c == len(l.chunks) {
+		c--
+		return c, len(l.chunks[c]), false
+	}
+	chunk := l.chunks[c]
+	i = sort.Search(len(chunk), func(i int) bool { return l.cmp(&chunk[i], x) >= 0 })
+	return c, i, l.cmp(&chunk[i], x) == 0
+}
+
+// insert adds x to the list, or replaces the element equal to x.
+// It reports whether x was added.
+func (l *sortedList[E]) insert(x E) bool {
+	if len(l.chunks) == 0 {
+		chunk := make([]E, 1, 2*sortedLoad)
+		chunk[0] = x
+		l.chunks = append(l.chunks, chunk)
+		l.n = 1
+		return true
+	}
+	c, i, found := l.search(&x)
+	if found {
+		l.chunks[c][i] = x
+		return false
+	}
+
+	chunk := append(l.chunks[c], x)
+	copy(chunk[i+1:], chunk[i:])
+	chunk[i] = x
+	l.chunks[c] = chunk
+	l.n++
+
+	if len(chunk) >= 2*sortedLoad {
+		// Split the chunk in two halves.
+		half := make([]E, len(chunk)-sortedLoad, 2*sortedLoad)
+		copy(half, chunk[sortedLoad:])
+		var zero E
+		for j := sortedLoad; j < len(chunk); j++ {
+			chunk[j] = zero // Don't retain references.
+		}
+		l.chunks[c] = chunk[:sortedLoad]
+		l.chunks = append(l.chunks, nil)
+		copy(l.chunks[c+2:], l.chunks[c+1:])
+		l.chunks[c+1] = half
+	}
+	return true
+}
+
+// delete removes the element equal to x, returning it and reporting whether it was present.
+func (l *sortedList[E]) delete(x *E) (E, bool) {
+	c, i, found := l.search(x)
+	if !found {
+		var zero E
+		return zero, false
+	}
+
+	chunk := l.chunks[c]
+	e := chunk[i]
+	copy(chunk[i:], chunk[i+1:])
+	var zero E
+	chunk[len(chunk)-1] = zero // Don't retain a reference.
+	l.chunks[c] = chunk[:len(chunk)-1]
+	l.n--
+
+	if len(l.chunks[c]) == 0 {
+		copy(l.chunks[c:], l.chunks[c+1:])
+		l.chunks[len(l.chunks)-1] = nil
+		l.chunks = l.chunks[:len(l.chunks)-1]
+	}
+	return e, true
+}
+
+// get returns the element equal to x, if any.
+func (l *sortedList[E]) get(x *E) (*E, bool) {
+	c, i, found := l.search(x)
+	if !found {
+		return nil, false
+	}
+	return &l.chunks[c][i], true
+}
+
+// floor returns the greatest element not greater than x, if any.
+func (l *sortedList[E]) floor(x *E) (*E, bool) {
+	c, i, found := l.search(x)
+	if found {
+		return &l.chunks[c][i], true
+	}
+	return l.before(c, i)
+}
+
+// ceiling returns the least element not less than x, if any.
+func (l *sortedList[E]) ceiling(x *E) (*E, bool) {
+	c, i, _ := l.search(x)
+	if len(l.chunks) == 0 || i == len(l.chunks[c]) {
+		return nil, false // All elements are less than x.
+	}
+	return &l.chunks[c][i], true
+}
+
+// before returns the element before position (c, i), if any.
+func (l *sortedList[E]) before(c, i int) (*E, bool) {
+	if i > 0 {
+		return &l.chunks[c][i-1], true
+	}
+	if c > 0 {
+		prev := l.chunks[c-1]
+		return &prev[len(prev)-1], true
+	}
+	return nil, false
+}
+
+// rank returns the number of elements less than x.
+func (l *sortedList[E]) rank(x *E) int {
+	c, i, _ := l.search(x)
+	for _, chunk := range l.chunks[:c] {
+		i += len(chunk)
+	}
+	return i
+}
+
+// at returns the element of rank r. It panics if r is out of range.
+func (l *sortedList[E]) at(r int) *E {
+	if r < 0 || r >= l.n {
+		panic("sorthelper: rank out of range")
+	}
+	for _, chunk := range l.chunks {
+		if r < len(chunk) {
+			return &chunk[r]
+		}
+		r -= len(chunk)
+	}
+	panic("unreachable")
+}
+
+// ascend calls f for the elements from position (c, i) on, in order,
+// until f returns false or an element not less than hi, if not nil, is met.
+func (l *sortedList[E]) ascend(c, i int, hi *E, f func(e *E) bool) {
+	for ; c < len(l.chunks); c, i = c+1, 0 {
+		chunk := l.chunks[c]
+		for ; i < len(chunk); i++ {
+			if hi != nil && l.cmp(&chunk[i], hi) >= 0 {
+				return
+			}
+			if !f(&chunk[i]) {
+				return
+			}
+		}
+	}
+}
+
+// descend calls f for all the elements in reverse order, until f returns false.
+func (l *sortedList[E]) descend(f func(e *E) bool) {
+	for c := len(l.chunks) - 1; c >= 0; c-- {
+		chunk := l.chunks[c]
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if !f(&chunk[i]) {
+				return
+			}
+		}
+	}
+}
+
+// load replaces the elements of the list with those of s, which must be
+// sorted and free of duplicates.
+func (l *sortedList[E]) load(s []E) {
+	l.chunks = l.chunks[:0]
+	for len(s) > 0 {
+		n := sortedLoad
+		if n > len(s) {
+			n = len(s)
+		}
+		chunk := make([]E, n, 2*sortedLoad)
+		copy(chunk, s)
+		l.chunks = append(l.chunks, chunk)
+		s = s[n:]
+	}
+	l.n = 0
+	for _, chunk := range l.chunks {
+		l.n += len(chunk)
+	}
+}
+
+// loadSorted implements LoadSorted for both SortedSet and SortedMap: it copies
+// s, sorts the copy unless s is already sorted, drops duplicates and loads it.
+func (l *sortedList[E]) loadSorted(s []E) {
+	s = append([]E(nil), s...)
+	less := LessFromCmp(l.cmp)
+	if !sort.SliceIsSorted(s, func(i, j int) bool { return less(&s[i], &s[j]) }) {
+		NewSorter(s).StableBy(less)
+	}
+	l.load(compact(s, less))
+}
+
+// SortedSet is a set of elements kept in sorted order, supporting ordered
+// queries such as Floor and Ceiling, rank queries and ordered iteration.
+// Insertions and deletions take O(log n) comparisons and move at most a few
+// hundred elements; rank queries take O(log n + n/512) time.
+// The zero SortedSet is not usable: create sets with NewSortedSet or NewSortedSetFunc.
+type SortedSet[E any] struct {
+	l sortedList[E]
+}
+
+// NewSortedSet returns an empty SortedSet ordered by the operator <.
+func NewSortedSet[E constraints.Ordered]() *SortedSet[E] {
+	return NewSortedSetFunc(Compare[E])
+}
+
+// NewSortedSetFunc returns an empty SortedSet ordered by the comparison function cmp,
+// as used by MultiSorter.OrderedByCmp. Elements comparing equal are the same element.
+func NewSortedSetFunc[E any](cmp func(e1, e2 *E) int) *SortedSet[E] {
+	return &SortedSet[E]{l: sortedList[E]{cmp: cmp}}
+}
+
+// Len returns the number of elements in the set.
+func (s *SortedSet[E]) Len() int { return s.l.n }
+
+// Insert adds e to the set, replacing the element equal to e if any.
+// It reports whether e was not already present.
+func (s *SortedSet[E]) Insert(e E) bool { return s.l.insert(e) }
+
+// Delete removes e from the set and reports whether it was present.
+func (s *SortedSet[E]) Delete(e E) bool {
+	_, ok := s.l.delete(&e)
+	return ok
+}
+
+// Contains reports whether e is in the set.
+func (s *SortedSet[E]) Contains(e E) bool {
+	_, ok := s.l.get(&e)
+	return ok
+}
+
+// Floor returns the greatest element of the set not greater than e,
+// and reports whether there is one.
+func (s *SortedSet[E]) Floor(e E) (E, bool) { return deref(s.l.floor(&e)) }
+
+// Ceiling returns the least element of the set not less than e,
+// and reports whether there is one.
+func (s *SortedSet[E]) Ceiling(e E) (E, bool) { return deref(s.l.ceiling(&e)) }
+
+// Rank returns the number of elements of the set less than e.
+func (s *SortedSet[E]) Rank(e E) int { return s.l.rank(&e) }
+
+// Select returns the element of rank i, that is the i'th smallest element
+// counting from 0. It panics if i is out of range.
+func (s *SortedSet[E]) Select(i int) E { return *s.l.at(i) }
+
+// Ascend calls f for each element of the set in increasing order,
+// until f returns false.
+func (s *SortedSet[E]) Ascend(f func(e E) bool) {
+	s.l.ascend(0, 0, nil, func(e *E) bool { return f(*e) })
+}
+
+// AscendRange calls f for each element of the set in the range [lo, hi)
+// in increasing order, until f returns false.
+func (s *SortedSet[E]) AscendRange(lo, hi E, f func(e E) bool) {
+	c, i, _ := s.l.search(&lo)
+	s.l.ascend(c, i, &hi, func(e *E) bool { return f(*e) })
+}
+
+// Descend calls f for each element of the set in decreasing order,
+// until f returns false.
+func (s *SortedSet[E]) Descend(f func(e E) bool) {
+	s.l.descend(func(e *E) bool { return f(*e) })
+}
+
+// LoadSorted replaces the contents of the set with the elements of s.
+// It takes O(n) time when s is already sorted, which is checked, and sorts
+// a copy of s otherwise. Only the first of equal elements of s is kept.
+func (s *SortedSet[E]) LoadSorted(elems []E) { s.l.loadSorted(elems) }
+
+// deref returns the value pointed to by p, or the zero value if ok is false.
+func deref[E any](p *E, ok bool) (E, bool) {
+	if !ok {
+		var zero E
+		return zero, false
+	}
+	return *p, true
+}
+
+// mapEntry is a key-value pair of a SortedMap.
+type mapEntry[K, V any] struct {
+	key K
+	val V
+}
+
+// SortedMap is a map whose keys are kept in sorted order, supporting ordered
+// queries such as Floor and Ceiling, rank queries and ordered iteration,
+// with the same costs as SortedSet.
+// The zero SortedMap is not usable: create maps with NewSortedMap or NewSortedMapFunc.
+type SortedMap[K, V any] struct {
+	l sortedList[mapEntry[K, V]]
+}
+
+// NewSortedMap returns an empty SortedMap with keys ordered by the operator <.
+func NewSortedMap[K constraints.Ordered, V any]() *SortedMap[K, V] {
+	return NewSortedMapFunc[K, V](Compare[K])
+}
+
+// NewSortedMapFunc returns an empty SortedMap with keys ordered by the comparison
+// function cmp, as used by MultiSorter.OrderedByCmp. Keys comparing equal are the same key.
+func NewSortedMapFunc[K, V any](cmp func(k1, k2 *K) int) *SortedMap[K, V] {
+	return &SortedMap[K, V]{l: sortedList[mapEntry[K, V]]{
+		cmp: func(e1, e2 *mapEntry[K, V]) int { return cmp(&e1.key, &e2.key) },
+	}}
+}
+
+// Len returns the number of entries in the map.
+func (m *SortedMap[K, V]) Len() int { return m.l.n }
+
+// Insert sets the value of key to val, and reports whether key was not already present.
+func (m *SortedMap[K, V]) Insert(key K, val V) bool {
+	return m.l.insert(mapEntry[K, V]{key, val})
+}
+
+// Get returns the value of key, and reports whether key is present.
+func (m *SortedMap[K, V]) Get(key K) (V, bool) {
+	e, ok := m.l.get(&mapEntry[K, V]{key: key})
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.val, true
+}
+
+// Delete removes key from the map and reports whether it was present.
+func (m *SortedMap[K, V]) Delete(key K) bool {
+	_, ok := m.l.delete(&mapEntry[K, V]{key: key})
+	return ok
+}
+
+// Contains reports whether key is in the map.
+func (m *SortedMap[K, V]) Contains(key K) bool {
+	_, ok := m.l.get(&mapEntry[K, V]{key: key})
+	return ok
+}
+
+// Floor returns the entry with the greatest key not greater than key,
+// and reports whether there is one.
+func (m *SortedMap[K, V]) Floor(key K) (K, V, bool) {
+	return entry(m.l.floor(&mapEntry[K, V]{key: key}))
+}
+
+// Ceiling returns the entry with the least key not less than key,
+// and reports whether there is one.
+func (m *SortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return entry(m.l.ceiling(&mapEntry[K, V]{key: key}))
+}
+
+// Rank returns the number of keys of the map less than key.
+func (m *SortedMap[K, V]) Rank(key K) int { return m.l.rank(&mapEntry[K, V]{key: key}) }
+
+// Select returns the entry whose key has rank i, counting from 0.
+// It panics if i is out of range.
+func (m *SortedMap[K, V]) Select(i int) (K, V) {
+	e := m.l.at(i)
+	return e.key, e.val
+}
+
+// Ascend calls f for each entry of the map in increasing order of keys,
+// until f returns false.
+func (m *SortedMap[K, V]) Ascend(f func(key K, val V) bool) {
+	m.l.ascend(0, 0, nil, func(e *mapEntry[K, V]) bool { return f(e.key, e.val) })
+}
+
+// AscendRange calls f for each entry of the map with a key in the range [lo, hi)
+// in increasing order of keys, until f returns false.
+func (m *SortedMap[K, V]) AscendRange(lo, hi K, f func(key K, val V) bool) {
+	c, i, _ := m.l.search(&mapEntry[K, V]{key: lo})
+	m.l.ascend(c, i, &mapEntry[K, V]{key: hi}, func(e *mapEntry[K, V]) bool { return f(e.key, e.val) })
+}
+
+// Descend calls f for each entry of the map in decreasing order of keys,
+// until f returns false.
+func (m *SortedMap[K, V]) Descend(f func(key K, val V) bool) {
+	m.l.descend(func(e *mapEntry[K, V]) bool { return f(e.key, e.val) })
+}
+
+// LoadSorted replaces the contents of the map with the entries keys[i], vals[i].
+// It takes O(n) time when keys is already sorted, which is checked, and sorts
+// the entries otherwise. Only the first entry of equal keys is kept.
+// LoadSorted panics if keys and vals have different lengths.
+func (m *SortedMap[K, V]) LoadSorted(keys []K, vals []V) {
+	if len(keys) != len(vals) {
+		panic("sorthelper: LoadSorted with keys and values of different lengths")
+	}
+	entries := make([]mapEntry[K, V], len(keys))
+	for i := range entries {
+		entries[i] = mapEntry[K, V]{keys[i], vals[i]}
+	}
+	m.l.loadSorted(entries)
+}
+
+// entry returns the key and value of e, or zero values if ok is false.
+func entry[K, V any](e *mapEntry[K, V], ok bool) (K, V, bool) {
+	if !ok {
+		var (
+			key K
+			val V
+		)
+		return key, val, false
+	}
+	return e.key, e.val, true
+}
@@ -0,0 +1,235 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+// checkSortedSet compares the contents of s with the sorted slice want.
+func checkSortedSet(t *testing.T, s *SortedSet[int], want []int) {
+	t.Helper()
+	if s.Len() != len(want) {
+		t.Fatalf("Len = %d, want %d", s.Len(), len(want))
+	}
+	var got []int
+	s.Ascend(func(e int) bool {
+		got = append(got, e)
+		return true
+	})
+	if !equalInts(got, want) {
+		t.Fatalf("Ascend yields %d elements, not the %d expected in order", len(got), len(want))
+	}
+	i := len(want)
+	s.Descend(func(e int) bool {
+		i--
+		if e != want[i] {
+			t.Fatalf("Descend yields %d at %d, want %d", e, i, want[i])
+		}
+		return true
+	})
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSortedSet(t *testing.T) {
+	t.Parallel()
+
+	const n = 5000
+	r := rand.New(rand.NewSource(1))
+	s := NewSortedSet[int]()
+	model := map[int]bool{}
+	for i := 0; i < 4*n; i++ {
+		x := r.Intn(n)
+		if r.Intn(3) == 0 {
+			if got, want := s.Delete(x), model[x]; got != want {
+				t.Fatalf("Delete(%d) = %t, want %t", x, got, want)
+			}
+			delete(model, x)
+		} else {
+			if got, want := s.Insert(x), !model[x]; got != want {
+				t.Fatalf("Insert(%d) = %t, want %t", x, got, want)
+			}
+			model[x] = true
+		}
+	}
+	want := make([]int, 0, len(model))
+	for x := range model {
+		want = append(want, x)
+	}
+	sort.Ints(want)
+	checkSortedSet(t, s, want)
+
+	for x := -1; x <= n; x++ {
+		i := sort.SearchInts(want, x)
+		if got := s.Rank(x); got != i {
+			t.Fatalf("Rank(%d) = %d, want %d", x, got, i)
+		}
+		if got := s.Contains(x); got != model[x] {
+			t.Fatalf("Contains(%d) = %t, want %t", x, got, model[x])
+		}
+
+		got, ok := s.Ceiling(x)
+		if ok != (i < len(want)) || ok && got != want[i] {
+			t.Fatalf("Ceiling(%d) = %d, %t", x, got, ok)
+		}
+		j := sort.SearchInts(want, x+1) - 1
+		got, ok = s.Floor(x)
+		if ok != (j >= 0) || ok && got != want[j] {
+			t.Fatalf("Floor(%d) = %d, %t", x, got, ok)
+		}
+	}
+	for i, x := range want {
+		if got := s.Select(i); got != x {
+			t.Fatalf("Select(%d) = %d, want %d", i, got, x)
+		}
+	}
+
+	lo, hi := n/4, n/2
+	var got []int
+	s.AscendRange(lo, hi, func(e int) bool {
+		got = append(got, e)
+		return true
+	})
+	if w := want[sort.SearchInts(want, lo):sort.SearchInts(want, hi)]; !equalInts(got, w) {
+		t.Errorf("AscendRange(%d, %d) = %d elements, want %d", lo, hi, len(got), len(w))
+	}
+
+	count := 0
+	s.Ascend(func(int) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Errorf("Ascend called f %d times after it returned false, want 3", count)
+	}
+}
+
+func TestSortedSetSelectPanics(t *testing.T) {
+	t.Parallel()
+
+	s := NewSortedSet[int]()
+	s.Insert(1)
+	defer func() {
+		if recover() == nil {
+			t.Error("Select out of range did not panic")
+		}
+	}()
+	s.Select(1)
+}
+
+func TestSortedSetLoadSorted(t *testing.T) {
+	t.Parallel()
+
+	data := make([]int, 3000)
+	for i := range data {
+		data[i] = i / 2
+	}
+	s := NewSortedSet[int]()
+	s.Insert(-1)
+	s.LoadSorted(data)
+	want := make([]int, 1500)
+	for i := range want {
+		want[i] = i
+	}
+	checkSortedSet(t, s, want)
+
+	// Unsorted input is sorted rather than trusted, and left unchanged.
+	rand.New(rand.NewSource(1)).Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+	orig := append([]int(nil), data...)
+	s.LoadSorted(data)
+	checkSortedSet(t, s, want)
+	if !equalInts(data, orig) {
+		t.Error("LoadSorted modified its input")
+	}
+
+	// Inserting after a bulk load splits and fills chunks as usual.
+	for i := 1500; i < 2500; i++ {
+		s.Insert(i)
+		want = append(want, i)
+	}
+	checkSortedSet(t, s, want)
+}
+
+func TestSortedSetFunc(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		name string
+		n    int
+	}
+	s := NewSortedSetFunc(By(func(e *entry) string { return e.name }).Compare)
+	s.Insert(entry{"b", 1})
+	s.Insert(entry{"a", 2})
+	if s.Insert(entry{"b", 3}) {
+		t.Error("Insert of an equal element reported it as added")
+	}
+	if got := s.Select(1); got != (entry{"b", 3}) {
+		t.Errorf("Select(1) = %v, want the replaced element {b 3}", got)
+	}
+}
+
+func TestSortedMap(t *testing.T) {
+	t.Parallel()
+
+	m := NewSortedMap[string, int]()
+	for i, k := range []string{"delta", "alpha", "charlie", "bravo", "alpha"} {
+		m.Insert(k, i)
+	}
+	if m.Len() != 4 {
+		t.Fatalf("Len = %d, want 4", m.Len())
+	}
+	if v, ok := m.Get("alpha"); !ok || v != 4 {
+		t.Errorf("Get(alpha) = %d, %t, want 4, true", v, ok)
+	}
+	if k, v, ok := m.Floor("c"); !ok || k != "bravo" || v != 3 {
+		t.Errorf("Floor(c) = %q, %d, %t, want bravo, 3, true", k, v, ok)
+	}
+	if k, _, ok := m.Ceiling("e"); ok {
+		t.Errorf("Ceiling(e) = %q, want none", k)
+	}
+	if got := m.Rank("charlie"); got != 2 {
+		t.Errorf("Rank(charlie) = %d, want 2", got)
+	}
+	if k, v := m.Select(3); k != "delta" || v != 0 {
+		t.Errorf("Select(3) = %q, %d, want delta, 0", k, v)
+	}
+
+	var keys []string
+	m.AscendRange("b", "d", func(k string, _ int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if len(keys) != 2 || keys[0] != "bravo" || keys[1] != "charlie" {
+		t.Errorf("AscendRange(b, d) = %q, want [bravo charlie]", keys)
+	}
+
+	if !m.Delete("bravo") || m.Delete("bravo") || m.Contains("bravo") {
+		t.Error("Delete(bravo) did not remove bravo exactly once")
+	}
+
+	m.LoadSorted([]string{"x", "y", "y"}, []int{1, 2, 3})
+	keys = keys[:0]
+	m.Descend(func(k string, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if len(keys) != 2 || keys[0] != "y" || keys[1] != "x" {
+		t.Errorf("Descend after LoadSorted = %q, want [y x]", keys)
+	}
+	if v, _ := m.Get("y"); v != 2 {
+		t.Errorf("Get(y) = %d, want the first loaded value 2", v)
+	}
+}
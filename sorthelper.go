@@ -23,14 +23,24 @@ type IntSlice[E constraints.Integer] struct{ Slice[E] }
 
 func (x IntSlice[E]) Less(i, j int) bool { return x.Slice[i] < x.Slice[j] }
 
-// Sort is a convenience method: x.Sort() calls sort.Sort(x).
-func (x IntSlice[E]) Sort() { sort.Sort(x) }
+// Sort sorts x in increasing order, merging presorted runs in O(n).
+// Large slices use a radix sort with a scratch buffer of len(x) elements.
+func (x IntSlice[E]) Sort() {
+	if sortAdaptiveOrdered(x.Slice) {
+		return
+	}
+	if len(x.Slice) >= radixThreshold {
+		radixSort(x.Slice)
+		return
+	}
+	sort.Sort(x)
+}
 
 // Reverse is a convenience method: x.Reverse() calls sorrt.Sort(sort.Reverse(x)).
 func (x IntSlice[E]) Reverse() { sort.Sort(sort.Reverse(x)) }
 
-// Stable is a convenience method: x.Stable() calls sort.Stable(x).
-func (x IntSlice[E]) Stable() { sort.Stable(x) }
+// Stable sorts x in increasing order, keeping equal elements in their original order.
+func (x IntSlice[E]) Stable() { stableSort(x.Slice, orderedLess[E], -1) }
 
 // IsSorted is a convenience method: x.IsSorted() calls sort.IsSorted(x).
 func (x IntSlice[E]) IsSorted() bool { return sort.IsSorted(x) }
@@ -50,11 +60,15 @@ func (x Float64Slice[E]) Less(i, j int) bool {
 	return x.Slice[i] < x.Slice[j] || (math.IsNaN(float64(x.Slice[i])) && !math.IsNaN(float64(x.Slice[j])))
 }
 
-// Sort is a convenience method: x.Sort() calls sort.Sort(x).
-func (x Float64Slice[E]) Sort() { sort.Sort(x) }
+// Sort sorts x in increasing order, merging presorted runs in O(n).
+func (x Float64Slice[E]) Sort() {
+	if !sortAdaptive(x.Slice, floatLess[E]) {
+		sort.Sort(x)
+	}
+}
 
-// Stable is a convenience method: x.Stable() calls sort.Stable(x).
-func (x Float64Slice[E]) Stable() { sort.Stable(x) }
+// Stable sorts x in increasing order, keeping equal elements in their original order.
+func (x Float64Slice[E]) Stable() { stableSort(x.Slice, floatLess[E], -1) }
 
 // Reverse is a convenience method: x.Reverse() calls sort.Sort(sort.Reverse(x)).
 func (x Float64Slice[E]) Reverse() { sort.Sort(sort.Reverse(x)) }
@@ -62,17 +76,53 @@ func (x Float64Slice[E]) Reverse() { sort.Sort(sort.Reverse(x)) }
 // IsSorted is a convenience method: x.IsSorted() calls sort.IsSorted(x).
 func (x Float64Slice[E]) IsSorted() bool { return sort.IsSorted(x) }
 
+// Float32Slice implements sort.Interface by providing Less and using the Len and
+// Swap methods of the embedded slice value, sorting in increasing order,
+// with not-a-number (NaN) values ordered before other values.
+type Float32Slice[E ~float32] struct{ Slice[E] }
+
+// Less reports whether x[i] should be ordered before x[j], as required by the sort Interface.
+// Like Float64Slice.Less, it places NaN values before any others, by using:
+//
+//	x[i] < x[j] || (isNaN(x[i]) && !isNaN(x[j]))
+func (x Float32Slice[E]) Less(i, j int) bool {
+	return x.Slice[i] < x.Slice[j] || (isNaN(x.Slice[i]) && !isNaN(x.Slice[j]))
+}
+
+// Sort sorts x in increasing order, merging presorted runs in O(n).
+func (x Float32Slice[E]) Sort() {
+	if !sortAdaptive(x.Slice, floatLess[E]) {
+		sort.Sort(x)
+	}
+}
+
+// Stable sorts x in increasing order, keeping equal elements in their original order.
+func (x Float32Slice[E]) Stable() { stableSort(x.Slice, floatLess[E], -1) }
+
+// Reverse is a convenience method: x.Reverse() calls sort.Sort(sort.Reverse(x)).
+func (x Float32Slice[E]) Reverse() { sort.Sort(sort.Reverse(x)) }
+
+// IsSorted is a convenience method: x.IsSorted() calls sort.IsSorted(x).
+func (x Float32Slice[E]) IsSorted() bool { return sort.IsSorted(x) }
+
+// isNaN is a copy of math.IsNaN to avoid a conversion to float64.
+func isNaN[E constraints.Float](f E) bool { return f != f }
+
 // StringSlice implements sort.Interface by providing Less and using the Len and
 // Swap methods of the embedded slice value, sorting in increasing order.
 type StringSlice[E ~string] struct{ Slice[E] }
 
 func (x StringSlice[E]) Less(i, j int) bool { return x.Slice[i] < x.Slice[j] }
 
-// Sort is a convenience method: x.Sort() calls sort.Sort(x).
-func (x StringSlice[E]) Sort() { sort.Sort(x) }
+// Sort sorts x in increasing order, merging presorted runs in O(n).
+func (x StringSlice[E]) Sort() {
+	if !sortAdaptiveOrdered(x.Slice) {
+		sort.Sort(x)
+	}
+}
 
-// Stable is a convenience method: x.Stable() calls sort.Stable(x).
-func (x StringSlice[E]) Stable() { sort.Stable(x) }
+// Stable sorts x in increasing order, keeping equal elements in their original order.
+func (x StringSlice[E]) Stable() { stableSort(x.Slice, orderedLess[E], -1) }
 
 // Reverse is a convenience method: x.Reverse() calls sort.Sort(sort.Reverse(x)).
 func (x StringSlice[E]) Reverse() { sort.Sort(sort.Reverse(x)) }
@@ -89,6 +139,10 @@ func Ints[E constraints.Integer](x []E) { IntSlice[E]{x}.Sort() }
 // Not-a-number (NaN) values are ordered before other values.
 func Float64s[E ~float64](x []E) { Float64Slice[E]{x}.Sort() }
 
+// Float32s sorts a slice of float32s in increasing order.
+// Not-a-number (NaN) values are ordered before other values.
+func Float32s[E ~float32](x []E) { Float32Slice[E]{x}.Sort() }
+
 // Strings sorts a slice of strings in increasing order.
 func Strings[E ~string](x []E) { StringSlice[E]{x}.Sort() }
 
@@ -99,13 +153,19 @@ func IntsAreSorted[E constraints.Integer](x []E) bool { return IntSlice[E]{x}.Is
 // with not-a-number (NaN) values before any other values.
 func Float64sAreSorted[E ~float64](x []E) bool { return Float64Slice[E]{x}.IsSorted() }
 
+// Float32sAreSorted reports whether the slice s is sorted in increasing order,
+// with not-a-number (NaN) values before any other values.
+func Float32sAreSorted[E ~float32](x []E) bool { return Float32Slice[E]{x}.IsSorted() }
+
 // StringsAreSorted reports whether the slice x is sorted in increasing order.
 func StringsAreSorted[E ~string](x []E) bool { return StringSlice[E]{x}.IsSorted() }
 
 // Sorter joins a by function and a slice s to be sorted.
 type Sorter[E any] struct {
-	s  []E
-	by func(e1, e2 *E) bool // The function (closure) that defines the sort order.
+	s        []E
+	by       func(e1, e2 *E) bool // The function (closure) that defines the sort order.
+	progress func(done, total int)
+	obs      *Observer
 }
 
 // NewSorter returns a Sorter that sorts the slice s.
@@ -125,22 +185,67 @@ func (s *Sorter[E]) Less(i, j int) bool { return s.by(&s.s[i], &s.s[j]) }
 
 // OrderedBy sorts the slice within according to the by function.
 // The sort is not guaranteed to be stable. For a stable sort, use StableBy.
-func (s *Sorter[E]) OrderedBy(by func(e1, e2 *E) bool) {
+func (s *Sorter[E]) OrderedBy(by func(e1, e2 *E) bool) { s.orderedBy("Sorter.OrderedBy", by) }
+
+// orderedBy sorts the slice within according to the by function,
+// as a phase named name of the Observer of s if it has one.
+func (s *Sorter[E]) orderedBy(name string, by func(e1, e2 *E) bool) {
 	s.by = by
+	debugCheck(s.s, by)
+	if s.obs != nil {
+		s.obs.Observe(name, func() { sort.Sort(s.obs.Interface(s)) })
+		return
+	}
 	sort.Sort(s)
 }
 
 // StableBy sorts the slice within according to the by function,
 // while keeping the original order of equal elements.
-func (s *Sorter[E]) StableBy(by func(e1, e2 *E) bool) {
+// It uses an adaptive merge sort that takes advantage of the sorted runs in the slice,
+// with a scratch buffer of up to half the length of the slice.
+func (s *Sorter[E]) StableBy(by func(e1, e2 *E) bool) { s.stableBy("Sorter.StableBy", by, -1) }
+
+// StableByBuffer is like StableBy but caps the scratch buffer to n elements.
+// Merges of runs that don't fit in the buffer are done in place, with more moves,
+// down to n = 0, which sorts without allocating in O(n log² n) time.
+// A negative n leaves the buffer uncapped, as in StableBy.
+func (s *Sorter[E]) StableByBuffer(by func(e1, e2 *E) bool, n int) {
+	s.stableBy("Sorter.StableByBuffer", by, n)
+}
+
+// stableBy is like orderedBy but keeps the original order of equal elements,
+// with a scratch buffer of up to n elements, as StableByBuffer.
+func (s *Sorter[E]) stableBy(name string, by func(e1, e2 *E) bool, n int) {
 	s.by = by
-	sort.Stable(s)
+	debugCheck(s.s, by)
+	if s.obs != nil {
+		s.obs.Observe(name, func() { stableSort(s.s, ObserveLess(s.obs, by), n) })
+		return
+	}
+	stableSort(s.s, by, n)
+}
+
+// OrderedByCmp sorts the slice within according to the comparison function cmp,
+// which returns a negative number when e1 < e2, a positive number when e1 > e2
+// and zero when they are equal, like the functions of the standard cmp package.
+// The sort is not guaranteed to be stable. For a stable sort, use StableByCmp.
+func (s *Sorter[E]) OrderedByCmp(cmp func(e1, e2 *E) int) {
+	s.orderedBy("Sorter.OrderedByCmp", LessFromCmp(cmp))
+}
+
+// StableByCmp sorts the slice within according to the comparison function cmp,
+// while keeping the original order of equal elements.
+func (s *Sorter[E]) StableByCmp(cmp func(e1, e2 *E) int) {
+	s.stableBy("Sorter.StableByCmp", LessFromCmp(cmp), -1)
 }
 
 // MultiSorter implements the Sort interface, sorting the slice within.
 type MultiSorter[E any] struct {
-	s    []E
-	less []func(e1, e2 *E) bool
+	s        []E
+	less     []func(e1, e2 *E) bool
+	cmp      []func(e1, e2 *E) int // Used instead of less when set.
+	progress func(done, total int)
+	obs      *Observer
 }
 
 // NewMultiSorter returns a MulitSorter that sorts the argument slice.
@@ -159,8 +264,22 @@ func (ms *MultiSorter[E]) Swap(i, j int) { ms.s[i], ms.s[j] = ms.s[j], ms.s[i] }
 // less functions until it finds a comparison that discriminates between
 // the two items (one is less than the other).
 // Note that it can call the less functions twice per call.
-func (ms *MultiSorter[E]) Less(i, j int) bool {
-	p, q := &ms.s[i], &ms.s[j]
+// When sorting with comparison functions instead, each one is called at most once.
+func (ms *MultiSorter[E]) Less(i, j int) bool { return ms.lessElem(&ms.s[i], &ms.s[j]) }
+
+// lessElem is the element form of Less.
+func (ms *MultiSorter[E]) lessElem(p, q *E) bool {
+	if ms.cmp != nil {
+		for _, cmp := range ms.cmp {
+			if c := cmp(p, q); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	}
+	if len(ms.less) == 0 {
+		return false // No order: all the elements are equal.
+	}
 
 	// Try all but the last comparison.
 	k := 0
@@ -186,13 +305,50 @@ func (ms *MultiSorter[E]) Less(i, j int) bool {
 // OrderedBy sorts the slice within according to the less functions, in order.
 // The sort is not guaranteed to be stable. For a stable sort, use StableBy.
 func (ms *MultiSorter[E]) OrderedBy(less ...func(e1, e2 *E) bool) {
-	ms.less = less
-	sort.Sort(ms)
+	ms.less, ms.cmp = less, nil
+	ms.sort("MultiSorter.OrderedBy")
 }
 
 // StableBy sorts the slice within in ascending order as determined by the less functions, in order,
 // while keeping the original order of equal elements.
 func (ms *MultiSorter[E]) StableBy(less ...func(e1, e2 *E) bool) {
-	ms.less = less
-	sort.Stable(ms)
+	ms.less, ms.cmp = less, nil
+	ms.stable("MultiSorter.StableBy")
+}
+
+// OrderedByCmp sorts the slice within according to the comparison functions, in order.
+// Each comparison function returns a negative number when e1 < e2,
+// a positive number when e1 > e2 and zero when they are equal,
+// so it is called at most once per key for each pair of elements compared.
+// The sort is not guaranteed to be stable. For a stable sort, use StableByCmp.
+func (ms *MultiSorter[E]) OrderedByCmp(cmp ...func(e1, e2 *E) int) {
+	ms.less, ms.cmp = nil, cmp
+	ms.sort("MultiSorter.OrderedByCmp")
+}
+
+// StableByCmp sorts the slice within in ascending order as determined by the comparison functions, in order,
+// while keeping the original order of equal elements.
+func (ms *MultiSorter[E]) StableByCmp(cmp ...func(e1, e2 *E) int) {
+	ms.less, ms.cmp = nil, cmp
+	ms.stable("MultiSorter.StableByCmp")
+}
+
+// sort sorts the slice within, as a phase named name of the Observer of ms if it has one.
+func (ms *MultiSorter[E]) sort(name string) {
+	debugCheck(ms.s, ms.lessElem)
+	if ms.obs != nil {
+		ms.obs.Observe(name, func() { sort.Sort(ms.obs.Interface(ms)) })
+		return
+	}
+	sort.Sort(ms)
+}
+
+// stable is like sort but keeps the original order of equal elements.
+func (ms *MultiSorter[E]) stable(name string) {
+	debugCheck(ms.s, ms.lessElem)
+	if ms.obs != nil {
+		ms.obs.Observe(name, func() { stableSort(ms.s, ObserveLess(ms.obs, ms.lessElem), -1) })
+		return
+	}
+	stableSort(ms.s, ms.lessElem, -1)
 }
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	. "github.com/weiwenchen2022/sorthelper"
+	"golang.org/x/exp/constraints"
 )
 
 var ints = [...]int{74, 59, 238, -784, 9845, 959, 905, 0, 0, 42, 7586, -5467984, 7586}
@@ -45,6 +46,22 @@ func TestSortFloat64Slice(t *testing.T) {
 	}
 }
 
+func TestSortFloat32Slice(t *testing.T) {
+	t.Parallel()
+
+	var data [len(float64s)]float32
+	for i, f := range float64s {
+		data[i] = float32(f)
+	}
+	a := Float32Slice[float32]{data[:]}
+	a.Sort()
+
+	if !a.IsSorted() || !Float32sAreSorted(data[:]) {
+		t.Errorf("sorted %v", float64s)
+		t.Errorf("   got %v", data)
+	}
+}
+
 func TestSortStringSlice(t *testing.T) {
 	t.Parallel()
 
@@ -126,6 +143,42 @@ func TestSortLarge_Random(t *testing.T) {
 	}
 }
 
+func testRadixSort[E constraints.Integer](t *testing.T, r *rand.Rand, n int) {
+	data := make([]E, n)
+	for i := range data {
+		data[i] = E(r.Uint64())
+	}
+	want := append([]E(nil), data...)
+	sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+
+	Ints(data)
+	for i := range data {
+		if data[i] != want[i] {
+			t.Errorf("%T: data[%d] = %v, want %v", data, i, data[i], want[i])
+			return
+		}
+	}
+}
+
+func TestIntsRadix(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for _, n := range []int{0, 1, 255, 256, 1000, 10000} {
+		testRadixSort[int](t, r, n)
+		testRadixSort[int8](t, r, n)
+		testRadixSort[int16](t, r, n)
+		testRadixSort[int32](t, r, n)
+		testRadixSort[int64](t, r, n)
+		testRadixSort[uint](t, r, n)
+		testRadixSort[uint8](t, r, n)
+		testRadixSort[uint16](t, r, n)
+		testRadixSort[uint32](t, r, n)
+		testRadixSort[uint64](t, r, n)
+		testRadixSort[uintptr](t, r, n)
+	}
+}
+
 func TestReverseSortIntSlice(t *testing.T) {
 	t.Parallel()
 
@@ -407,6 +460,83 @@ func BenchmarkSortInt64K(b *testing.B) {
 	}
 }
 
+func BenchmarkSortInt64K_Random(b *testing.B) {
+	for _, bench := range [...]bench[int]{
+		{"sort.Ints", sort.Ints},
+		{"Ints", Ints[int]},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.StopTimer()
+			r := rand.New(rand.NewSource(1))
+			unsorted := make([]int, 1<<16)
+			for i := range unsorted {
+				unsorted[i] = r.Int()
+			}
+			data := make([]int, len(unsorted))
+
+			for i := 0; i < b.N; i++ {
+				copy(data, unsorted)
+				b.StartTimer()
+				bench.f(data)
+				b.StopTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkSortInt32_64K(b *testing.B) {
+	for _, bench := range [...]bench[int32]{
+		{
+			name: "sort.Slice",
+			f: func(data []int32) {
+				sort.Slice(data, func(i, j int) bool { return data[i] < data[j] })
+			},
+		},
+		{"Ints", Ints[int32]},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.StopTimer()
+			r := rand.New(rand.NewSource(1))
+			unsorted := make([]int32, 1<<16)
+			for i := range unsorted {
+				unsorted[i] = int32(r.Uint32())
+			}
+			data := make([]int32, len(unsorted))
+
+			for i := 0; i < b.N; i++ {
+				copy(data, unsorted)
+				b.StartTimer()
+				bench.f(data)
+				b.StopTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkSortInt1M(b *testing.B) {
+	for _, bench := range [...]bench[int]{
+		{"sort.Ints", sort.Ints},
+		{"Ints", Ints[int]},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.StopTimer()
+			r := rand.New(rand.NewSource(1))
+			unsorted := make([]int, 1<<20)
+			for i := range unsorted {
+				unsorted[i] = r.Int()
+			}
+			data := make([]int, len(unsorted))
+
+			for i := 0; i < b.N; i++ {
+				copy(data, unsorted)
+				b.StartTimer()
+				bench.f(data)
+				b.StopTimer()
+			}
+		})
+	}
+}
+
 func BenchmarkSortInt64K_Slice(b *testing.B) {
 	for _, bench := range [...]bench[int]{
 		{
@@ -0,0 +1,360 @@
+// This file implements the stable sort used by the types of this package.
+
+package sorthelper
+
+// stableSort sorts s stably as determined by less with an adaptive merge sort:
+// it merges the natural runs of s, extended to a minimum length by insertion sort,
+// as in TimSort. A merge copies the shorter of its two runs to a scratch buffer
+// of at most maxBuf elements, or merges them in place with rotations when the
+// buffer is too short; a negative maxBuf allows up to len(s)/2 elements.
+//
+// Sorted, reversed and partially sorted inputs take close to O(n) comparisons,
+// and the worst case is O(n log n) comparisons and moves with a full buffer.
+func stableSort[E any](s []E, less func(e1, e2 *E) bool, maxBuf int) {
+	n := len(s)
+	if n < 2 {
+		return
+	}
+	if maxBuf < 0 || maxBuf > n/2 {
+		maxBuf = n / 2
+	}
+	st := stableSorter[E]{less: less, maxBuf: maxBuf}
+
+	minRun := minRunLength(n)
+	for lo := 0; lo < n; {
+		r := st.countRun(s[lo:])
+		if r < minRun {
+			force := minRun
+			if force > n-lo {
+				force = n - lo
+			}
+			st.insertionSort(s[lo:lo+force], r)
+			r = force
+		}
+		st.runs = append(st.runs, stableRun{lo, r})
+		st.mergeCollapse(s)
+		lo += r
+	}
+	for len(st.runs) > 1 {
+		i := len(st.runs) - 2
+		if i > 0 && st.runs[i-1].n < st.runs[i+1].n {
+			i--
+		}
+		st.mergeAt(s, i)
+	}
+}
+
+// stableRun is a sorted run s[start:start+n] pending a merge.
+type stableRun struct {
+	start, n int
+}
+
+type stableSorter[E any] struct {
+	less   func(e1, e2 *E) bool
+	runs   []stableRun // Stack of pending runs.
+	buf    []E         // Scratch buffer, allocated on demand.
+	maxBuf int
+}
+
+// minGallop is the number of consecutive elements taken from the same run
+// during a merge after which the merge switches to galloping search.
+const minGallop = 7
+
+// minRunLength returns the minimum run length for sorting n elements: a length
+// between 16 and 32 such that n/minRun is close to a power of two,
+// which keeps the final merges balanced.
+func minRunLength(n int) int {
+	r := 0
+	for n >= 32 {
+		r |= n & 1
+		n >>= 1
+	}
+	return n + r
+}
+
+// countRun returns the length of the run at the beginning of s,
+// reversing it in place if it is strictly descending.
+func (st *stableSorter[E]) countRun(s []E) int {
+	n, desc := runLength(s, st.less)
+	if desc {
+		reverse(s[:n])
+	}
+	return n
+}
+
+// insertionSort sorts s, whose first sorted elements are already sorted,
+// with binary insertion sort.
+func (st *stableSorter[E]) insertionSort(s []E, sorted int) {
+	for i := sorted; i < len(s); i++ {
+		lo, hi := 0, i
+		for lo < hi {
+			h := int(uint(lo+hi) >> 1)
+			if st.less(&s[i], &s[h]) {
+				hi = h
+			} else {
+				lo = h + 1
+			}
+		}
+		x := s[i]
+		copy(s[lo+1:i+1], s[lo:i])
+		s[lo] = x
+	}
+}
+
+// mergeCollapse merges the runs at the top of the stack until their lengths
+// decrease at least as fast as the Fibonacci numbers, so that the stack
+// stays O(log n) deep and merges are balanced.
+func (st *stableSorter[E]) mergeCollapse(s []E) {
+	for len(st.runs) > 1 {
+		i := len(st.runs) - 2
+		r := st.runs
+		if i > 0 && r[i-1].n <= r[i].n+r[i+1].n || i > 1 && r[i-2].n <= r[i-1].n+r[i].n {
+			if r[i-1].n < r[i+1].n {
+				i--
+			}
+		} else if r[i].n > r[i+1].n {
+			break
+		}
+		st.mergeAt(s, i)
+	}
+}
+
+// mergeAt merges the runs i and i+1 of the stack.
+func (st *stableSorter[E]) mergeAt(s []E, i int) {
+	a, b := st.runs[i], st.runs[i+1]
+	st.merge(s[a.start:b.start+b.n], a.n)
+	st.runs[i].n += b.n
+	st.runs = append(st.runs[:i+1], st.runs[i+2:]...)
+}
+
+// merge merges the sorted runs s[:mid] and s[mid:].
+func (st *stableSorter[E]) merge(s []E, mid int) {
+	if mid == 0 || mid == len(s) {
+		return
+	}
+
+	// The elements of the first run not greater than the first element
+	// of the second run are already in place, and so are the elements of
+	// the second run not less than the last element of the first run.
+	k := st.gallopPrefix(s[:mid], &s[mid], true)
+	s, mid = s[k:], mid-k
+	if mid == 0 {
+		return
+	}
+	s = s[:len(s)-st.gallopSuffix(s[mid:], &s[mid-1], true)]
+
+	switch n := len(s) - mid; {
+	case mid <= n && mid <= st.maxBuf:
+		st.mergeLo(s, mid)
+	case n < mid && n <= st.maxBuf:
+		st.mergeHi(s, mid)
+	default:
+		st.mergeInPlace(s, mid)
+	}
+}
+
+// gallopPrefix returns the number of leading elements of s less than x,
+// or not greater than x if inclusive is set. Like gallopSearch, it takes
+// O(log k) comparisons to return k.
+func (st *stableSorter[E]) gallopPrefix(s []E, x *E, inclusive bool) int {
+	lo, hi := 0, 0 // s[:lo] is in the prefix.
+	for hi < len(s) && st.before(&s[hi], x, inclusive) {
+		lo, hi = hi+1, 2*hi+1
+	}
+	if hi > len(s) {
+		hi = len(s)
+	}
+	for lo < hi {
+		h := int(uint(lo+hi) >> 1)
+		if st.before(&s[h], x, inclusive) {
+			lo = h + 1
+		} else {
+			hi = h
+		}
+	}
+	return lo
+}
+
+// gallopSuffix returns the number of trailing elements of s greater than x,
+// or not less than x if inclusive is set.
+func (st *stableSorter[E]) gallopSuffix(s []E, x *E, inclusive bool) int {
+	n := len(s)
+	lo, hi := 0, 0 // s[n-lo:] is in the suffix.
+	for hi < n && st.before(x, &s[n-1-hi], inclusive) {
+		lo, hi = hi+1, 2*hi+1
+	}
+	if hi > n {
+		hi = n
+	}
+	for lo < hi {
+		h := int(uint(lo+hi) >> 1)
+		if st.before(x, &s[n-1-h], inclusive) {
+			lo = h + 1
+		} else {
+			hi = h
+		}
+	}
+	return lo
+}
+
+// before reports whether e1 < e2, or e1 <= e2 if inclusive is set.
+func (st *stableSorter[E]) before(e1, e2 *E, inclusive bool) bool {
+	if inclusive {
+		return !st.less(e2, e1)
+	}
+	return st.less(e1, e2)
+}
+
+// buffer returns a scratch buffer of n elements, growing the buffer
+// as merges need it, up to maxBuf elements.
+func (st *stableSorter[E]) buffer(n int) []E {
+	if cap(st.buf) < n {
+		c := 2 * cap(st.buf)
+		if c < n {
+			c = n
+		}
+		if c > st.maxBuf {
+			c = st.maxBuf
+		}
+		st.buf = make([]E, n, c)
+	}
+	return st.buf[:n]
+}
+
+// mergeLo merges the sorted runs s[:mid] and s[mid:], where the first run
+// is the shorter one, by moving it to the buffer and merging from the front.
+func (st *stableSorter[E]) mergeLo(s []E, mid int) {
+	buf := st.buffer(mid)
+	copy(buf, s[:mid])
+
+	i, j, k := 0, mid, 0
+	wins := 0 // Consecutive elements taken from the same run; positive for buf.
+	for i < len(buf) && j < len(s) {
+		if st.less(&s[j], &buf[i]) {
+			s[k] = s[j]
+			j++
+			if wins > 0 {
+				wins = 0
+			}
+			wins--
+		} else {
+			s[k] = buf[i]
+			i++
+			if wins < 0 {
+				wins = 0
+			}
+			wins++
+		}
+		k++
+
+		if i == len(buf) || j == len(s) {
+			break
+		}
+		switch {
+		case wins >= minGallop:
+			// Take the elements of buf not greater than s[j] at once.
+			n := st.gallopPrefix(buf[i:], &s[j], true)
+			k += copy(s[k:], buf[i:i+n])
+			i += n
+			wins = 0
+		case wins <= -minGallop:
+			// Take the elements of s[j:] less than buf[i] at once.
+			n := st.gallopPrefix(s[j:], &buf[i], false)
+			k += copy(s[k:], s[j:j+n])
+			j += n
+			wins = 0
+		}
+	}
+	copy(s[k:], buf[i:])
+}
+
+// mergeHi merges the sorted runs s[:mid] and s[mid:], where the second run
+// is the shorter one, by moving it to the buffer and merging from the back.
+func (st *stableSorter[E]) mergeHi(s []E, mid int) {
+	buf := st.buffer(len(s) - mid)
+	copy(buf, s[mid:])
+
+	i, j, k := mid-1, len(buf)-1, len(s)-1
+	wins := 0 // Consecutive elements taken from the same run; positive for buf.
+	for i >= 0 && j >= 0 {
+		if st.less(&buf[j], &s[i]) {
+			s[k] = s[i]
+			i--
+			if wins > 0 {
+				wins = 0
+			}
+			wins--
+		} else {
+			s[k] = buf[j]
+			j--
+			if wins < 0 {
+				wins = 0
+			}
+			wins++
+		}
+		k--
+
+		if i < 0 || j < 0 {
+			break
+		}
+		switch {
+		case wins >= minGallop:
+			// Take the elements of buf not less than s[i] at once.
+			n := st.gallopSuffix(buf[:j+1], &s[i], true)
+			copy(s[k-n+1:k+1], buf[j-n+1:j+1])
+			j, k = j-n, k-n
+			wins = 0
+		case wins <= -minGallop:
+			// Take the elements of s[:i+1] greater than buf[j] at once.
+			n := st.gallopSuffix(s[:i+1], &buf[j], false)
+			copy(s[k-n+1:k+1], s[i-n+1:i+1])
+			i, k = i-n, k-n
+			wins = 0
+		}
+	}
+	copy(s[:j+1], buf[:j+1])
+}
+
+// mergeInPlace merges the sorted runs s[:mid] and s[mid:] when the buffer is
+// too short, with the SymMerge algorithm of Pok-Son Kim and Arne Kutzner:
+// it rotates the middle of s so as to leave two smaller merges, which are
+// done with the buffer once they are short enough.
+func (st *stableSorter[E]) mergeInPlace(s []E, mid int) {
+	n := len(s)
+	half := n / 2
+	p := half + mid
+	var start, r int
+	if mid > half {
+		start, r = p-n, half
+	} else {
+		start, r = 0, mid
+	}
+	for start < r {
+		c := int(uint(start+r) >> 1)
+		if !st.less(&s[p-1-c], &s[c]) {
+			start = c + 1
+		} else {
+			r = c
+		}
+	}
+	end := p - start
+	if start < mid && mid < end {
+		rotate(s[start:end], mid-start)
+	}
+	st.merge(s[:half], start)
+	st.merge(s[half:], end-half)
+}
+
+// reverse reverses the elements of s.
+func reverse[E any](s []E) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// rotate rotates s left by k elements.
+func rotate[E any](s []E, k int) {
+	reverse(s[:k])
+	reverse(s[k:])
+	reverse(s)
+}
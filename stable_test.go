@@ -0,0 +1,154 @@
+package sorthelper_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+// stableInputs returns inputs of length n exercising the runs, merges and
+// galloping of the stable sort, with few distinct keys to test stability.
+func stableInputs(r *rand.Rand, n int) map[string][]int {
+	inputs := map[string][]int{}
+	add := func(name string, f func(i int) int) {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = f(i)
+		}
+		inputs[name] = s
+	}
+	add("random", func(int) int { return r.Intn(n/4 + 1) })
+	add("sorted", func(i int) int { return i / 3 })
+	add("reversed", func(i int) int { return n - i })
+	add("mod8", func(i int) int { return i % 8 })
+	add("sawtooth", func(i int) int { return i % 100 })
+	add("descending runs", func(i int) int { return i/50*50 + 50 - i%50 })
+	add("organ pipe", func(i int) int {
+		if i < n/2 {
+			return i
+		}
+		return n - i
+	})
+	add("nearly sorted", func(i int) int {
+		if r.Intn(20) == 0 {
+			return r.Intn(n)
+		}
+		return i
+	})
+	add("interleaved blocks", func(i int) int { return i%64*1000 + i/64 })
+	return inputs
+}
+
+type keyIndex struct{ key, index int }
+
+func TestStableByBuffer(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 31, 64, 1000, 5000} {
+		for name, input := range stableInputs(r, n) {
+			for _, buf := range []int{-1, 0, 1, 7, 100} {
+				data := make([]keyIndex, n)
+				for i, k := range input {
+					data[i] = keyIndex{k, i}
+				}
+				want := append([]keyIndex(nil), data...)
+				sort.SliceStable(want, func(i, j int) bool { return want[i].key < want[j].key })
+
+				NewSorter(data).StableByBuffer(func(e1, e2 *keyIndex) bool { return e1.key < e2.key }, buf)
+				// Compared element for element, which also catches lost or duplicated elements.
+				for i := range want {
+					if data[i] != want[i] {
+						t.Fatalf("%s n=%d buffer=%d: data[%d] = %v, want %v", name, n, buf, i, data[i], want[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestStableTypes(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(2))
+	ints := make([]int, 3000)
+	strs := make([]string, 3000)
+	for i := range ints {
+		ints[i] = r.Intn(500)
+		strs[i] = fmt.Sprint(r.Intn(500))
+	}
+
+	IntSlice[int]{ints}.Stable()
+	if !sort.IntsAreSorted(ints) {
+		t.Error("IntSlice.Stable didn't sort")
+	}
+	StringSlice[string]{strs}.Stable()
+	if !sort.StringsAreSorted(strs) {
+		t.Error("StringSlice.Stable didn't sort")
+	}
+
+	type rec struct{ a, b, i int }
+	recs := make([]rec, 3000)
+	for i := range recs {
+		recs[i] = rec{r.Intn(5), r.Intn(5), i}
+	}
+	NewMultiSorter(recs).StableByCmp(
+		func(r1, r2 *rec) int { return r1.a - r2.a },
+		func(r1, r2 *rec) int { return r1.b - r2.b },
+	)
+	for i := 1; i < len(recs); i++ {
+		p, q := recs[i-1], recs[i]
+		if p.a > q.a || p.a == q.a && (p.b > q.b || p.b == q.b && p.i > q.i) {
+			t.Fatalf("MultiSorter.StableByCmp: %v before %v", p, q)
+		}
+	}
+}
+
+func BenchmarkStableInt64K_PartiallySorted(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	unsorted := make([]int, 1<<16)
+	for i := range unsorted {
+		unsorted[i] = i
+	}
+	// Disturb one element in 32, leaving long sorted runs.
+	for i := 0; i < len(unsorted)/32; i++ {
+		j := r.Intn(len(unsorted))
+		unsorted[j] = r.Intn(len(unsorted))
+	}
+	for _, bench := range [...]bench[int]{
+		{"sort.Stable.IntSlice", func(data []int) { sort.Stable(sort.IntSlice(data)) }},
+		{"IntSlice.Stable", func(data []int) { IntSlice[int]{data}.Stable() }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			data := make([]int, len(unsorted))
+			b.StopTimer()
+			for i := 0; i < b.N; i++ {
+				copy(data, unsorted)
+				b.StartTimer()
+				bench.f(data)
+				b.StopTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkStableByBuffer64K(b *testing.B) {
+	for _, n := range []int{-1, 1 << 10, 0} {
+		b.Run(fmt.Sprint("buffer=", n), func(b *testing.B) {
+			data := make([]int, 1<<16)
+			less := func(i1, i2 *int) bool { return *i1 < *i2 }
+			b.StopTimer()
+			for i := 0; i < b.N; i++ {
+				for i := range data {
+					data[i] = i ^ 0xcccc
+				}
+				b.StartTimer()
+				NewSorter(data).StableByBuffer(less, n)
+				b.StopTimer()
+			}
+		})
+	}
+}
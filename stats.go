@@ -0,0 +1,234 @@
+// This file implements the instrumentation of sorts.
+
+package sorthelper
+
+import (
+	"runtime"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Stats holds the measurements of an Observer.
+type Stats struct {
+	Comparisons int64         // Calls of observed less functions.
+	Swaps       int64         // Calls of the Swap methods of observed sort.Interfaces.
+	Allocs      uint64        // Heap objects allocated.
+	AllocBytes  uint64        // Bytes of heap memory allocated.
+	Elapsed     time.Duration // Wall-clock time.
+}
+
+// add adds the measurements of t to s.
+func (s *Stats) add(t Stats) {
+	s.Comparisons += t.Comparisons
+	s.Swaps += t.Swaps
+	s.Allocs += t.Allocs
+	s.AllocBytes += t.AllocBytes
+	s.Elapsed += t.Elapsed
+}
+
+// A Phase holds the measurements of a phase of work run by Observer.Observe.
+type Phase struct {
+	Name string
+	Stats
+}
+
+// A MetricReporter reports custom benchmark metrics. It is implemented by *testing.B.
+type MetricReporter interface {
+	ReportMetric(n float64, unit string)
+}
+
+// An Observer measures sorts: it counts the comparisons and swaps made through
+// the less functions and sort.Interfaces it wraps, with ObserveLess and Interface,
+// and measures the time and allocations of the phases of work run by Observe.
+// Sorter and MultiSorter use an Observer set by their Observe method
+// to measure each of their sorts as a phase.
+//
+// Sorts that take no less function, such as Ints, are measured for time
+// and allocations only. The merge sort of the stable sorts moves elements
+// without swapping them, so only its comparisons are counted.
+//
+// The functions returned by ObserveLess and Interface count with plain integers,
+// to keep the cost of counting low, and must not be called concurrently.
+// The parallel sorts of Sorter and MultiSorter count atomically instead.
+// Observe must not be called concurrently.
+//
+// The zero Observer is ready to use.
+type Observer struct {
+	// First, for the 64-bit alignment of the atomic operations of the parallel sorts.
+	comparisons int64
+	swaps       int64
+
+	total  Stats // The measurements of the outermost phases.
+	phases []Phase
+	depth  int // Nesting depth of the running phases.
+}
+
+// ObserveLess returns a less function calling less and counting its calls in o.
+func ObserveLess[E any](o *Observer, less func(e1, e2 *E) bool) func(e1, e2 *E) bool {
+	return func(e1, e2 *E) bool {
+		o.comparisons++
+		return less(e1, e2)
+	}
+}
+
+// Interface returns a sort.Interface calling the methods of data
+// and counting the calls of Less and Swap in o.
+func (o *Observer) Interface(data sort.Interface) sort.Interface {
+	return &observedInterface{data, o}
+}
+
+type observedInterface struct {
+	sort.Interface
+	o *Observer
+}
+
+func (x *observedInterface) Less(i, j int) bool {
+	x.o.comparisons++
+	return x.Interface.Less(i, j)
+}
+
+func (x *observedInterface) Swap(i, j int) {
+	x.o.swaps++
+	x.Interface.Swap(i, j)
+}
+
+// Observe runs f as a phase named name, recording its comparisons, swaps,
+// allocations and elapsed time. Phases can be nested.
+//
+// Observe reads the memory statistics of the runtime before and after f,
+// which stops the world briefly: in benchmarks, the time of small sorts
+// is inflated by it.
+func (o *Observer) Observe(name string, f func()) {
+	var m0, m1 runtime.MemStats
+	comparisons, swaps := o.comparisons, o.swaps
+	runtime.ReadMemStats(&m0)
+	start := time.Now()
+
+	func() {
+		o.depth++
+		defer func() { o.depth-- }()
+		f()
+	}()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&m1)
+	p := Phase{name, Stats{
+		Comparisons: o.comparisons - comparisons,
+		Swaps:       o.swaps - swaps,
+		Allocs:      m1.Mallocs - m0.Mallocs,
+		AllocBytes:  m1.TotalAlloc - m0.TotalAlloc,
+		Elapsed:     elapsed,
+	}}
+	o.phases = append(o.phases, p)
+	if o.depth == 0 {
+		o.total.add(p.Stats)
+	}
+}
+
+// Stats returns the measurements of o: all the comparisons and swaps counted
+// and the allocations and elapsed time of the phases run by Observe.
+func (o *Observer) Stats() Stats {
+	s := o.total
+	s.Comparisons, s.Swaps = o.comparisons, o.swaps
+	return s
+}
+
+// Phases returns the measurements of the phases run by Observe, in the order they ended.
+func (o *Observer) Phases() []Phase { return o.phases }
+
+// Reset clears the measurements of o.
+func (o *Observer) Reset() {
+	o.comparisons, o.swaps = 0, 0
+	o.total = Stats{}
+	o.phases = o.phases[:0]
+}
+
+// Report reports the measurements of o to r as metrics per operation,
+// for n operations: comparisons as "cmps/op" and swaps as "swaps/op",
+// and, if Observe was used, allocations as "sort-allocs/op", bytes allocated
+// as "sort-B/op" and elapsed time as "sort-ns/op".
+// Benchmarks call it with b.N after their loop:
+//
+//	var o sorthelper.Observer
+//	for i := 0; i < b.N; i++ {
+//		sorthelper.NewSorter(data).Observe(&o).OrderedBy(less)
+//	}
+//	o.Report(b, b.N)
+func (o *Observer) Report(r MetricReporter, n int) {
+	s := o.Stats()
+	r.ReportMetric(float64(s.Comparisons)/float64(n), "cmps/op")
+	r.ReportMetric(float64(s.Swaps)/float64(n), "swaps/op")
+	if len(o.phases) > 0 {
+		r.ReportMetric(float64(s.Allocs)/float64(n), "sort-allocs/op")
+		r.ReportMetric(float64(s.AllocBytes)/float64(n), "sort-B/op")
+		r.ReportMetric(float64(s.Elapsed.Nanoseconds())/float64(n), "sort-ns/op")
+	}
+}
+
+// observeLessConcurrent is like ObserveLess, but counts atomically,
+// for less functions called concurrently.
+func observeLessConcurrent[E any](o *Observer, less func(e1, e2 *E) bool) func(e1, e2 *E) bool {
+	return func(e1, e2 *E) bool {
+		atomic.AddInt64(&o.comparisons, 1)
+		return less(e1, e2)
+	}
+}
+
+// concurrentInterface is like observedInterface, but counts atomically,
+// for sort.Interfaces used concurrently.
+type concurrentInterface struct {
+	sort.Interface
+	o *Observer
+}
+
+func (x *concurrentInterface) Less(i, j int) bool {
+	atomic.AddInt64(&x.o.comparisons, 1)
+	return x.Interface.Less(i, j)
+}
+
+func (x *concurrentInterface) Swap(i, j int) {
+	atomic.AddInt64(&x.o.swaps, 1)
+	x.Interface.Swap(i, j)
+}
+
+// observe runs f as a phase of o, or just runs it if o is nil.
+func (o *Observer) observe(name string, f func()) {
+	if o == nil {
+		f()
+		return
+	}
+	o.Observe(name, f)
+}
+
+// wrap returns data observed by o, or data itself if o is nil.
+func (o *Observer) wrap(data sort.Interface) sort.Interface {
+	if o == nil {
+		return data
+	}
+	return o.Interface(data)
+}
+
+// wrapConcurrent is like wrap, for a sort.Interface used concurrently.
+func (o *Observer) wrapConcurrent(data sort.Interface) sort.Interface {
+	if o == nil {
+		return data
+	}
+	return &concurrentInterface{data, o}
+}
+
+// Observe sets the Observer measuring the sorts of s, as phases named after
+// the method sorting, such as "Sorter.OrderedBy". A nil o stops the measurements.
+// It returns s.
+func (s *Sorter[E]) Observe(o *Observer) *Sorter[E] {
+	s.obs = o
+	return s
+}
+
+// Observe sets the Observer measuring the sorts of ms, as phases named after
+// the method sorting, such as "MultiSorter.OrderedBy". A nil o stops the measurements.
+// It returns ms.
+func (ms *MultiSorter[E]) Observe(o *Observer) *MultiSorter[E] {
+	ms.obs = o
+	return ms
+}
@@ -0,0 +1,145 @@
+package sorthelper_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+// TestObserver is not parallel: the allocations measured by Observe include those of other goroutines.
+func TestObserver(t *testing.T) {
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = (i * 7919) % len(data)
+	}
+	less := func(i1, i2 *int) bool { return *i1 < *i2 }
+
+	var o Observer
+	s := append([]int(nil), data...)
+	NewSorter(s).Observe(&o).OrderedBy(less)
+	if !sort.IntsAreSorted(s) {
+		t.Fatal("observed OrderedBy didn't sort")
+	}
+	st := o.Stats()
+	if st.Comparisons == 0 || st.Swaps == 0 || st.Elapsed <= 0 {
+		t.Errorf("Stats after OrderedBy = %+v", st)
+	}
+	if p := o.Phases(); len(p) != 1 || p[0].Name != "Sorter.OrderedBy" || p[0].Stats != st {
+		t.Errorf("Phases after OrderedBy = %+v, want one phase with %+v", p, st)
+	}
+
+	s = append(s[:0], data...)
+	o.Observe("stable", func() {
+		NewMultiSorter(s).Observe(&o).StableBy(less)
+		o.Observe("Ints", func() { Ints(append([]int(nil), data...)) })
+	})
+	if !sort.IntsAreSorted(s) {
+		t.Fatal("observed StableBy didn't sort")
+	}
+	p := o.Phases()
+	if len(p) != 4 || p[1].Name != "MultiSorter.StableBy" || p[2].Name != "Ints" || p[3].Name != "stable" {
+		t.Fatalf("Phases = %+v", p)
+	}
+	if p[1].Comparisons == 0 || p[1].Swaps != 0 || p[3].Comparisons != p[1].Comparisons {
+		t.Errorf("StableBy phases = %+v, %+v", p[1], p[3])
+	}
+	if p[2].Comparisons != 0 || p[2].Allocs == 0 {
+		t.Errorf("Ints phase = %+v", p[2])
+	}
+	// The nested phases are counted once in the totals.
+	if st := o.Stats(); st.Elapsed != p[0].Elapsed+p[3].Elapsed || st.Allocs != p[0].Allocs+p[3].Allocs {
+		t.Errorf("Stats = %+v, want the totals of phases %+v and %+v", st, p[0], p[3])
+	}
+
+	o.Reset()
+	if st := o.Stats(); st != (Stats{}) || len(o.Phases()) != 0 {
+		t.Errorf("Stats after Reset = %+v, phases %v", st, o.Phases())
+	}
+}
+
+func TestObserverSorters(t *testing.T) {
+	t.Parallel()
+
+	less := func(i1, i2 *int) bool { return *i1 < *i2 }
+	for _, n := range []int{100, 20000} { // Sorted serially, then in parallel.
+		for _, tt := range []struct {
+			name  string
+			sort  func(o *Observer, s []int)
+			swaps bool
+		}{
+			{"Sorter.ParallelOrderedBy", func(o *Observer, s []int) { NewSorter(s).Observe(o).ParallelOrderedBy(4, less) }, true},
+			{"Sorter.ParallelStableBy", func(o *Observer, s []int) { NewSorter(s).Observe(o).ParallelStableBy(4, less) }, false},
+			{"MultiSorter.ParallelOrderedBy", func(o *Observer, s []int) { NewMultiSorter(s).Observe(o).ParallelOrderedBy(4, less) }, true},
+			{"MultiSorter.ParallelStableBy", func(o *Observer, s []int) { NewMultiSorter(s).Observe(o).ParallelStableBy(4, less) }, false},
+			{"MultiSorter.OrderedByKeys", func(o *Observer, s []int) {
+				NewMultiSorter(s).Observe(o).OrderedByKeys(ByKey(func(i *int) int { return *i }))
+			}, true},
+			{"MultiSorter.StableByKeys", func(o *Observer, s []int) {
+				NewMultiSorter(s).Observe(o).StableByKeys(ByKey(func(i *int) int { return *i }))
+			}, true},
+			{"Sorter.OrderedByCmp", func(o *Observer, s []int) {
+				NewSorter(s).Observe(o).OrderedByCmp(func(i1, i2 *int) int { return *i1 - *i2 })
+			}, true},
+			{"Sorter.StableByBuffer", func(o *Observer, s []int) { NewSorter(s).Observe(o).StableByBuffer(less, 10) }, false},
+		} {
+			s := rand.New(rand.NewSource(1)).Perm(n)
+			var o Observer
+			tt.sort(&o, s)
+			if !sort.IntsAreSorted(s) {
+				t.Errorf("observed %s didn't sort %d elements", tt.name, n)
+			}
+			p := o.Phases()
+			if len(p) != 1 || p[0].Name != tt.name {
+				t.Errorf("%s of %d elements: phases %+v", tt.name, n, p)
+				continue
+			}
+			if p[0].Comparisons < int64(n-1) || (p[0].Swaps > 0) != tt.swaps {
+				t.Errorf("%s of %d elements: %+v", tt.name, n, p[0])
+			}
+		}
+	}
+}
+
+func TestObserverPanic(t *testing.T) {
+	t.Parallel()
+
+	var o Observer
+	func() {
+		defer func() { recover() }()
+		o.Observe("panic", func() { panic("sort failed") })
+	}()
+	o.Observe("after", func() {})
+	if st, p := o.Stats(), o.Phases(); len(p) != 1 || st.Elapsed != p[0].Elapsed {
+		t.Errorf("after a panicking phase, Stats = %+v, want those of phases %+v", st, p)
+	}
+}
+
+func BenchmarkObservedSortInt1K_Mod8(b *testing.B) {
+	less := func(i1, i2 *int) bool { return *i1 < *i2 }
+	for _, bench := range [...]struct {
+		name string
+		f    func(o *Observer, data []int)
+	}{
+		{"sort.Sort", func(o *Observer, data []int) { sort.Sort(o.Interface(sort.IntSlice(data))) }},
+		{"Sorter.OrderedBy", func(o *Observer, data []int) { NewSorter(data).Observe(o).OrderedBy(less) }},
+		{"Sorter.StableBy", func(o *Observer, data []int) { NewSorter(data).Observe(o).StableBy(less) }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			var o Observer
+			b.StopTimer()
+			for i := 0; i < b.N; i++ {
+				data := make([]int, 1<<10)
+				for i := range data {
+					data[i] = i % 8
+				}
+
+				b.StartTimer()
+				bench.f(&o, data)
+				b.StopTimer()
+			}
+			o.Report(b, b.N)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// This file implements the IEEE 754 totalOrder of floating-point values.
+
+package sorthelper
+
+import (
+	"math"
+	"sort"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// totalKey returns the bits of f transformed so that their unsigned order
+// is the IEEE 754 totalOrder of floats: negative values have all their bits
+// flipped and positive values their sign bit set.
+func totalKey[E constraints.Float](f E) uint64 {
+	if unsafe.Sizeof(f) == 4 {
+		return uint64(toTotal32(math.Float32bits(float32(f))))
+	}
+	return toTotal64(math.Float64bits(float64(f)))
+}
+
+func toTotal32(b uint32) uint32 {
+	if b>>31 != 0 {
+		return ^b
+	}
+	return b | 1<<31
+}
+
+func fromTotal32(k uint32) uint32 {
+	if k>>31 != 0 {
+		return k &^ (1 << 31)
+	}
+	return ^k
+}
+
+func toTotal64(b uint64) uint64 {
+	if b>>63 != 0 {
+		return ^b
+	}
+	return b | 1<<63
+}
+
+func fromTotal64(k uint64) uint64 {
+	if k>>63 != 0 {
+		return k &^ (1 << 63)
+	}
+	return ^k
+}
+
+// TotalCompare compares a and b according to the totalOrder predicate of IEEE 754,
+// returning -1 if a is ordered before b, +1 if a is ordered after b and 0 if they
+// have the same bits. The order is:
+//
+//	-NaN < -Inf < negative numbers < -0 < +0 < positive numbers < +Inf < +NaN
+//
+// where NaNs with the same sign are ordered by their payloads, larger payloads
+// being further from zero.
+// TotalCompare[float64] can be passed to PtrCmp to sort with Sorter.
+func TotalCompare[E constraints.Float](a, b E) int {
+	ka, kb := totalKey(a), totalKey(b)
+	switch {
+	case ka < kb:
+		return -1
+	case ka > kb:
+		return +1
+	}
+	return 0
+}
+
+// TotalLess reports whether a is ordered before b according to TotalCompare.
+func TotalLess[E constraints.Float](a, b E) bool { return totalKey(a) < totalKey(b) }
+
+// SortTotalOrder sorts a slice of floats in increasing order according to TotalCompare.
+// It rewrites the bits of the floats in place into keys sorted as integers,
+// so it is much faster than a comparison sort on large slices.
+func SortTotalOrder[E constraints.Float](x []E) {
+	if len(x) < 2 {
+		return
+	}
+
+	if unsafe.Sizeof(x[0]) == 4 {
+		u := unsafe.Slice((*uint32)(unsafe.Pointer(&x[0])), len(x))
+		for i := range u {
+			u[i] = toTotal32(u[i])
+		}
+		Ints(u)
+		for i := range u {
+			u[i] = fromTotal32(u[i])
+		}
+		return
+	}
+
+	u := unsafe.Slice((*uint64)(unsafe.Pointer(&x[0])), len(x))
+	for i := range u {
+		u[i] = toTotal64(u[i])
+	}
+	Ints(u)
+	for i := range u {
+		u[i] = fromTotal64(u[i])
+	}
+}
+
+// TotalOrderIsSorted reports whether the slice x is sorted in increasing order
+// according to TotalCompare.
+func TotalOrderIsSorted[E constraints.Float](x []E) bool {
+	for i := len(x) - 1; i > 0; i-- {
+		if TotalLess(x[i], x[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchTotalOrder searches for x in a slice of floats sorted by SortTotalOrder
+// and returns the index of the first element not ordered before x.
+// The return value is the index to insert x if x is not present (it could be len(a)).
+// Unlike SearchFloat64s it tells -0 from +0 and NaNs apart.
+func SearchTotalOrder[E constraints.Float](a []E, x E) int {
+	k := totalKey(x)
+	return sort.Search(len(a), func(i int) bool { return totalKey(a[i]) >= k })
+}
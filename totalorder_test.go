@@ -0,0 +1,95 @@
+package sorthelper_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestSearchFloatsNaN(t *testing.T) {
+	t.Parallel()
+
+	nan := math.NaN()
+	a := []float64{5, nan, 1, nan, math.Inf(-1), 3}
+	Float64s(a)
+	if i := SearchFloat64s(a, nan); i != 0 {
+		t.Errorf("SearchFloat64s(NaN) = %d, want 0", i)
+	}
+	if i := SearchFloat64s(a, math.Inf(-1)); i != 2 {
+		t.Errorf("SearchFloat64s(-Inf) = %d, want 2", i)
+	}
+
+	p := Float64Slice[float64]{a}
+	if lo, hi := p.EqualRange(nan); lo != 0 || hi != 2 {
+		t.Errorf("EqualRange(NaN) = %d, %d, want 0, 2", lo, hi)
+	}
+	if i, found := p.Find(nan); i != 0 || !found {
+		t.Errorf("Find(NaN) = %d, %t, want 0, true", i, found)
+	}
+	if i, found := p.Find(4); i != 5 || found {
+		t.Errorf("Find(4) = %d, %t, want 5, false", i, found)
+	}
+
+	b := []float32{float32(nan), 2, -1, float32(nan)}
+	Float32s(b)
+	q := Float32Slice[float32]{b}
+	if n := q.Count(float32(nan)); n != 2 {
+		t.Errorf("Float32Slice.Count(NaN) = %d, want 2", n)
+	}
+	if i := q.Search(2); i != 3 {
+		t.Errorf("Float32Slice.Search(2) = %d, want 3", i)
+	}
+}
+
+func TestTotalOrder(t *testing.T) {
+	t.Parallel()
+
+	negNaN := math.Float64frombits(0xfff8000000000001)
+	bigNaN := math.Float64frombits(0x7ff8000000000002)
+	nan := math.Float64frombits(0x7ff8000000000001)
+	negZero := math.Copysign(0, -1)
+	ordered := []float64{negNaN, math.Inf(-1), -1, negZero, 0, 1, math.Inf(1), nan, bigNaN}
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = +1
+			}
+			if got := TotalCompare(ordered[i], ordered[j]); got != want {
+				t.Errorf("TotalCompare(%x, %x) = %d, want %d",
+					math.Float64bits(ordered[i]), math.Float64bits(ordered[j]), got, want)
+			}
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{len(ordered), 1000} {
+		data := make([]float64, n)
+		for i := range data {
+			data[i] = ordered[r.Intn(len(ordered))]
+		}
+		SortTotalOrder(data)
+		if !TotalOrderIsSorted(data) {
+			t.Errorf("SortTotalOrder didn't sort %d floats", n)
+		}
+		for _, x := range ordered {
+			i := SearchTotalOrder(data, x)
+			if i < len(data) && math.Float64bits(data[i]) != math.Float64bits(x) && TotalLess(data[i], x) {
+				t.Errorf("SearchTotalOrder(%v) = %d", x, i)
+			}
+			if i > 0 && !TotalLess(data[i-1], x) {
+				t.Errorf("SearchTotalOrder(%v) = %d, not the first position", x, i)
+			}
+		}
+	}
+
+	f32 := []float32{1, float32(negZero), float32(math.Inf(-1)), 0, float32(nan), -2}
+	SortTotalOrder(f32)
+	if !TotalOrderIsSorted(f32) || math.Signbit(float64(f32[2])) != true || !math.IsNaN(float64(f32[5])) {
+		t.Errorf("SortTotalOrder(float32) = %v", f32)
+	}
+}
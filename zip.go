@@ -0,0 +1,205 @@
+// This file implements co-sorting of parallel slices.
+
+package sorthelper
+
+import (
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+)
+
+// A ZipColumn is a column sorted along with the others by a ZipSorter.
+// Any slice can be used as one by converting it to Slice.
+type ZipColumn interface {
+	Len() int
+	Swap(i, j int)
+}
+
+// A KeyColumn is a column whose values order the rows of a ZipSorter,
+// created by ZipKey, ZipKeyDesc or ZipKeyFunc.
+type KeyColumn struct {
+	col ZipColumn
+	cmp func(i, j int) int // Compares the values of the rows i and j.
+}
+
+// ZipKey returns a key column ordering the rows in increasing order of the values of s,
+// with not-a-number (NaN) values ordered before other values.
+func ZipKey[E constraints.Ordered](s []E) KeyColumn {
+	return KeyColumn{Slice[E](s), func(i, j int) int { return compareOrdered(s[i], s[j]) }}
+}
+
+// ZipKeyDesc returns a key column ordering the rows in decreasing order of the values of s,
+// with not-a-number (NaN) values ordered after other values.
+func ZipKeyDesc[E constraints.Ordered](s []E) KeyColumn {
+	return KeyColumn{Slice[E](s), func(i, j int) int { return compareOrdered(s[j], s[i]) }}
+}
+
+// ZipKeyFunc returns a key column ordering the rows by the values of s as determined
+// by the comparison function cmp, as used by MultiSorter.OrderedByCmp.
+func ZipKeyFunc[E any](s []E, cmp func(e1, e2 *E) int) KeyColumn {
+	return KeyColumn{Slice[E](s), func(i, j int) int { return cmp(&s[i], &s[j]) }}
+}
+
+// ZipSorter sorts parallel slices, or columns, together: it orders the rows,
+// made of the values at the same index in every column, by its key columns,
+// in order, and moves the values of every column in lockstep.
+//
+// It first sorts the row indexes, comparing the key columns, then applies
+// the resulting permutation to each column with O(n) swaps, so that the cost
+// of additional columns doesn't depend on the number of comparisons.
+// A slice can be used as several columns, such as a slice of structs used
+// as several keys with ZipKeyFunc, but columns must not otherwise overlap.
+type ZipSorter struct {
+	keys []KeyColumn
+	cols []ZipColumn
+	n    int
+}
+
+// NewZipSorter returns a ZipSorter ordering rows by the key columns, in order:
+// rows with equal values in a key column are ordered by the next one.
+// It panics if the columns don't all have the same length.
+func NewZipSorter(keys ...KeyColumn) *ZipSorter {
+	z := &ZipSorter{keys: keys, n: -1}
+	for _, k := range keys {
+		z.check(k.col)
+	}
+	return z
+}
+
+// Columns adds companion columns to z, which are sorted along with the key columns,
+// and returns z. It panics if the columns don't all have the same length.
+func (z *ZipSorter) Columns(cols ...ZipColumn) *ZipSorter {
+	for _, c := range cols {
+		z.check(c)
+	}
+	z.cols = append(z.cols, cols...)
+	return z
+}
+
+func (z *ZipSorter) check(c ZipColumn) {
+	if z.n < 0 {
+		z.n = c.Len()
+	} else if c.Len() != z.n {
+		panic("sorthelper: ZipSorter columns of different lengths")
+	}
+}
+
+// Len returns the number of rows.
+func (z *ZipSorter) Len() int {
+	if z.n < 0 {
+		return 0
+	}
+	return z.n
+}
+
+// less reports whether the row i is ordered before the row j.
+func (z *ZipSorter) less(i, j int) bool {
+	for _, k := range z.keys {
+		if c := k.cmp(i, j); c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// Sort sorts the rows by the key columns.
+// The sort is not guaranteed to be stable. For a stable sort, use Stable.
+func (z *ZipSorter) Sort() {
+	perm := identity(z.Len())
+	NewSorter(perm).OrderedBy(func(p, q *int) bool { return z.less(*p, *q) })
+	z.apply(perm)
+}
+
+// Stable sorts the rows by the key columns, keeping the original order of equal rows.
+func (z *ZipSorter) Stable() {
+	perm := identity(z.Len())
+	stableSort(perm, func(p, q *int) bool { return z.less(*p, *q) }, -1)
+	z.apply(perm)
+}
+
+// IsSorted reports whether the rows are sorted by the key columns.
+func (z *ZipSorter) IsSorted() bool {
+	for i := z.Len() - 1; i > 0; i-- {
+		if z.less(i, i-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply rearranges every column with the permutation perm.
+// Columns that are slices backed by the same array, from the same
+// element with the same length, are rearranged once, so that a slice
+// can be used as several keys, or as a key and a companion column.
+func (z *ZipSorter) apply(perm []int) {
+	type backing struct {
+		p    uintptr // Address of the first element.
+		n    int
+		elem reflect.Type
+	}
+	seen := make(map[backing]bool)
+	apply := func(c ZipColumn) {
+		if v := reflect.ValueOf(c); v.Kind() == reflect.Slice && v.Len() > 0 {
+			b := backing{v.Pointer(), v.Len(), v.Type().Elem()}
+			if seen[b] {
+				return
+			}
+			seen[b] = true
+		}
+		permuteSwap(c, perm)
+	}
+	for _, k := range z.keys {
+		apply(k.col)
+	}
+	for _, c := range z.cols {
+		apply(c)
+	}
+}
+
+// permuteSwap rearranges c so that its new i'th value is its old perm[i]'th one,
+// like permute, with one swap per value moved. perm is only read.
+func permuteSwap(c ZipColumn, perm []int) {
+	placed := newBitset(len(perm))
+	for i := range perm {
+		if placed.has(i) {
+			continue // Already placed by an earlier cycle.
+		}
+
+		// The value of i moves along the cycle, into the place of each
+		// value brought to its place by a swap.
+		j := i
+		for perm[j] != i {
+			k := perm[j]
+			c.Swap(j, k)
+			placed.add(j)
+			j = k
+		}
+		placed.add(j)
+	}
+}
+
+// ZipSort2 sorts keys in increasing order, with not-a-number (NaN) values first,
+// moving the values of vals along with their keys.
+// The sort is not guaranteed to be stable. For a stable sort, use ZipStable2.
+// It panics if keys and vals have different lengths.
+func ZipSort2[K constraints.Ordered, V any](keys []K, vals []V) {
+	NewZipSorter(ZipKey(keys)).Columns(Slice[V](vals)).Sort()
+}
+
+// ZipStable2 is like ZipSort2 but keeps the original order of equal keys.
+func ZipStable2[K constraints.Ordered, V any](keys []K, vals []V) {
+	NewZipSorter(ZipKey(keys)).Columns(Slice[V](vals)).Stable()
+}
+
+// ZipSort3 sorts keys in increasing order, with not-a-number (NaN) values first,
+// moving the values of vals1 and vals2 along with their keys.
+// The sort is not guaranteed to be stable. For a stable sort, use ZipStable3.
+// It panics if the slices have different lengths.
+func ZipSort3[K constraints.Ordered, V1, V2 any](keys []K, vals1 []V1, vals2 []V2) {
+	NewZipSorter(ZipKey(keys)).Columns(Slice[V1](vals1), Slice[V2](vals2)).Sort()
+}
+
+// ZipStable3 is like ZipSort3 but keeps the original order of equal keys.
+func ZipStable3[K constraints.Ordered, V1, V2 any](keys []K, vals1 []V1, vals2 []V2) {
+	NewZipSorter(ZipKey(keys)).Columns(Slice[V1](vals1), Slice[V2](vals2)).Stable()
+}
@@ -0,0 +1,122 @@
+package sorthelper_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	. "github.com/weiwenchen2022/sorthelper"
+)
+
+func TestZipSorter(t *testing.T) {
+	t.Parallel()
+
+	const n = 2000
+	r := rand.New(rand.NewSource(1))
+	type row struct {
+		a   int64
+		b   string
+		val float64
+		id  int
+	}
+	for _, stable := range []bool{false, true} {
+		as := make([]int64, n)
+		bs := make([]string, n)
+		vals := make([]float64, n)
+		ids := make([]int, n)
+		rows := make(map[int]row, n)
+		for i := 0; i < n; i++ {
+			as[i], bs[i], vals[i], ids[i] = int64(r.Intn(10)), fmt.Sprint(r.Intn(10)), r.Float64(), i
+			rows[i] = row{as[i], bs[i], vals[i], i}
+		}
+
+		z := NewZipSorter(ZipKey(as), ZipKeyDesc(bs)).Columns(Slice[float64](vals), Slice[int](ids))
+		if stable {
+			z.Stable()
+		} else {
+			z.Sort()
+		}
+		if !z.IsSorted() {
+			t.Fatalf("stable %t: IsSorted = false after sorting", stable)
+		}
+		for i := 0; i < n; i++ {
+			// Every row moved in lockstep.
+			if got, want := (row{as[i], bs[i], vals[i], ids[i]}), rows[ids[i]]; got != want {
+				t.Fatalf("stable %t: row %d = %v, want %v", stable, i, got, want)
+			}
+			if i == 0 {
+				continue
+			}
+			switch {
+			case as[i-1] > as[i]:
+			case as[i-1] == as[i] && bs[i-1] < bs[i]:
+			case stable && as[i-1] == as[i] && bs[i-1] == bs[i] && ids[i-1] > ids[i]:
+			default:
+				continue
+			}
+			t.Fatalf("stable %t: row %d = %v out of order after %v", stable, i,
+				rows[ids[i]], rows[ids[i-1]])
+		}
+	}
+}
+
+func TestZipSortN(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"c", "a", "b", "a"}
+	vals := []int{3, 1, 2, 4}
+	ZipStable2(keys, vals)
+	if fmt.Sprint(keys, vals) != "[a a b c] [1 4 2 3]" {
+		t.Errorf("ZipStable2 = %v %v", keys, vals)
+	}
+
+	ks := []float64{2, 1, 3}
+	v1 := []string{"two", "one", "three"}
+	v2 := []byte{'b', 'a', 'c'}
+	ZipSort3(ks, v1, v2)
+	if fmt.Sprint(ks, v1, v2) != "[1 2 3] [one two three] [97 98 99]" {
+		t.Errorf("ZipSort3 = %v %v %q", ks, v1, v2)
+	}
+
+	type point struct{ x, y int }
+	pts := []point{{2, 1}, {1, 2}, {1, 1}}
+	names := []string{"p", "q", "r"}
+	NewZipSorter(ZipKeyFunc(pts, func(p1, p2 *point) int {
+		if p1.x != p2.x {
+			return p1.x - p2.x
+		}
+		return p1.y - p2.y
+	})).Columns(Slice[string](names)).Sort()
+	if fmt.Sprint(names) != "[r q p]" {
+		t.Errorf("ZipKeyFunc sort = %v, want [r q p]", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("ZipSort2 with slices of different lengths did not panic")
+		}
+	}()
+	ZipSort2([]int{1, 2}, []int{1})
+}
+
+func TestZipSorterSharedColumns(t *testing.T) {
+	t.Parallel()
+
+	type point struct{ a, b int }
+	pts := []point{{2, 1}, {1, 2}, {3, 0}, {1, 1}, {2, 0}}
+	names := []string{"v", "w", "x", "y", "z"}
+	NewZipSorter(
+		ZipKeyFunc(pts, func(p1, p2 *point) int { return p1.a - p2.a }),
+		ZipKeyFunc(pts, func(p1, p2 *point) int { return p1.b - p2.b }),
+	).Columns(Slice[string](names)).Stable()
+	if got := fmt.Sprint(pts, names); got != "[{1 1} {1 2} {2 0} {2 1} {3 0}] [y w z v x]" {
+		t.Errorf("sort by two keys of one column = %s", got)
+	}
+
+	k := []int{3, 1, 2}
+	v := []string{"c", "a", "b"}
+	NewZipSorter(ZipKey(k)).Columns(Slice[int](k), Slice[string](v)).Sort()
+	if got := fmt.Sprint(k, v); got != "[1 2 3] [a b c]" {
+		t.Errorf("sort with a key also used as a column = %s", got)
+	}
+}